@@ -0,0 +1,15 @@
+package main
+
+import (
+	"jooble-parser/internal/artifacts"
+	"jooble-parser/internal/config"
+
+	"go.uber.org/zap"
+)
+
+func makeArtifacts(cfg *config.Config, logger *zap.Logger) artifacts.Store {
+	if !cfg.Artifacts.Enabled {
+		return artifacts.NewNopStore()
+	}
+	return artifacts.NewDirStore(&cfg.Artifacts, logger)
+}
@@ -9,7 +9,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func makeDiff(cfg *config.Config, logger *zap.Logger) differ.Differ {
+func makeJobService(cfg *config.Config, logger *zap.Logger) service.JobService {
 	dbCfg := cfg.DB
 	jobService, err := service.NewSqliteRepoService(
 		dbCfg.Path,
@@ -19,7 +19,18 @@ func makeDiff(cfg *config.Config, logger *zap.Logger) differ.Differ {
 	if err != nil {
 		panic(fmt.Sprintf("Error creating job service: %v", err))
 	}
+	return jobService
+}
+
+func makeFingerprintService(cfg *config.Config, logger *zap.Logger) service.FingerprintService {
+	fingerprints, err := service.NewSqliteFingerprintService(cfg.DB.Path, logger)
+	if err != nil {
+		panic(fmt.Sprintf("Error creating fingerprint service: %v", err))
+	}
+	return fingerprints
+}
 
+func makeDiff(jobService service.JobService) differ.Differ {
 	dif := differ.NewDefaultDiffer(jobService)
 	return dif
 }
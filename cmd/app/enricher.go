@@ -0,0 +1,16 @@
+package main
+
+import (
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/enricher"
+	"jooble-parser/internal/service"
+
+	"go.uber.org/zap"
+)
+
+func makeEnricher(cfg *config.Config, jobService service.JobService, logger *zap.Logger) enricher.Enricher {
+	if !cfg.Enrich.Enabled {
+		return enricher.NewNopEnricher()
+	}
+	return enricher.NewDetailEnricher(cfg, jobService, logger)
+}
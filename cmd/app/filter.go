@@ -0,0 +1,10 @@
+package main
+
+import (
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/filter"
+)
+
+func makeFilter(cfg *config.Config) filter.Filter {
+	return filter.NewJobFilter(&cfg.Filter)
+}
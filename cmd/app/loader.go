@@ -1,12 +1,33 @@
 package main
 
 import (
+	"fmt"
+	"jooble-parser/internal/artifacts"
 	"jooble-parser/internal/config"
 	"jooble-parser/internal/loader"
 
 	"go.uber.org/zap"
 )
 
-func makeLoader(cfg *config.Config, logger *zap.Logger) loader.HtmlLoader {
-	return loader.NewChromeLoader(&cfg.Chrome, logger)
+func makeLoader(cfg *config.Config, kind string, store artifacts.Store, logger *zap.Logger) loader.HtmlLoader {
+	var htmlLoader loader.HtmlLoader
+
+	switch kind {
+	case config.LoaderHttp:
+		htmlLoader = loader.NewHttpLoader(cfg, store, logger)
+	case config.LoaderReplay:
+		replay, err := loader.NewReplayLoader(cfg.Loader.ReplayDir, logger)
+		if err != nil {
+			panic(fmt.Sprintf("Error creating replay loader: %v", err))
+		}
+		htmlLoader = replay
+	default:
+		htmlLoader = loader.NewChromeLoader(cfg, store, logger)
+	}
+
+	if cfg.Loader.RecordDir != "" {
+		htmlLoader = loader.NewRecordingLoader(htmlLoader, cfg.Loader.RecordDir, logger)
+	}
+
+	return htmlLoader
 }
@@ -3,18 +3,23 @@ package main
 import (
 	"context"
 	"jooble-parser/internal/app"
+	"jooble-parser/internal/normalize"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	_ "time/tzdata"
 )
 
 func main() {
 	cfg := makeConfig()
 	logger := makeLogger(cfg)
-	htmlLoader := makeLoader(cfg, logger)
-	jobsParser := makeParser(logger)
-	dif := makeDiff(cfg, logger)
+	store := makeArtifacts(cfg, logger)
+	jobSources := makeSources(cfg, store, logger)
+	jobService := makeJobService(cfg, logger)
+	jobEnricher := makeEnricher(cfg, jobService, logger)
+	dif := makeDiff(jobService)
+	jobFilter := makeFilter(cfg)
 	signal := makeUpdateSignal(cfg, logger)
 
 	defer logger.Sync()
@@ -22,9 +27,12 @@ func main() {
 	app := app.New(
 		cfg,
 		logger,
-		htmlLoader,
-		jobsParser,
+		jobSources,
+		normalize.NewNormalizer(cfg.Parsing.GetLocation()),
+		jobEnricher,
 		dif,
+		jobService,
+		jobFilter,
 		signal)
 
 	app.Run(gracefulShutDown())
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"fmt"
+	"jooble-parser/internal/config"
 	"jooble-parser/internal/parser"
 	"jooble-parser/internal/parser/setters"
 
 	"go.uber.org/zap"
 )
 
-func makeParser(logger *zap.Logger) *parser.JobParser {
-	return parser.NewJobParser(logger, setters.AllSetters...)
+func makeParser(cfg *config.Config, spec *setters.Spec, logger *zap.Logger) *parser.JobParser {
+	jobParser, err := parser.NewJobParser(logger, spec, &cfg.Parsing.Health)
+	if err != nil {
+		panic(fmt.Sprintf("Error creating job parser: %v", err))
+	}
+	return jobParser
 }
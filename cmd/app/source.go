@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"jooble-parser/internal/artifacts"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/loader"
+	"jooble-parser/internal/sites"
+	"jooble-parser/internal/source"
+
+	"go.uber.org/zap"
+)
+
+func makeSources(cfg *config.Config, store artifacts.Store, logger *zap.Logger) []source.JobSource {
+	switch cfg.Parsing.Source {
+	case config.SourceApi:
+		return []source.JobSource{source.NewApiSource(cfg, logger)}
+	case config.SourceXhr:
+		return []source.JobSource{source.NewXhrSource(
+			loader.NewChromeCapturer(cfg, store, logger),
+			cfg.Parsing.Url,
+			cfg.Parsing.XhrPattern,
+			logger)}
+	default:
+		return makeHtmlSources(cfg, store, logger)
+	}
+}
+
+// makeHtmlSources watches every search url with the loader of its site.
+// Sites using the same kind of loader share it, so there is one browser at
+// most.
+func makeHtmlSources(cfg *config.Config, store artifacts.Store, logger *zap.Logger) []source.JobSource {
+	registry, err := sites.NewRegistry(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("Error creating site registry: %v", err))
+	}
+
+	fingerprints := makeFingerprintService(cfg, logger)
+	loaders := make(map[string]*loader.SharedLoader)
+
+	var sources []source.JobSource
+	for _, search := range cfg.Parsing.SearchUrls() {
+		site, err := registry.Lookup(search)
+		if err != nil {
+			panic(fmt.Sprintf("Error finding site of search url: %v", err))
+		}
+
+		// recorded pages are replayed whatever loaded them
+		kind := cfg.Loader.Kind
+		if site.Loader != "" && kind != config.LoaderReplay {
+			kind = site.Loader
+		}
+		if kind == "" {
+			kind = config.LoaderChrome
+		}
+
+		shared, ok := loaders[kind]
+		if !ok {
+			shared = loader.NewSharedLoader(makeLoader(cfg, kind, store, logger))
+			loaders[kind] = shared
+		}
+
+		siteLogger := logger.With(zap.String("site", site.Name))
+		sources = append(sources, source.NewHtmlSource(
+			site,
+			shared.Acquire(),
+			makeParser(cfg, site.Spec, siteLogger),
+			search,
+			fingerprints,
+			store,
+			siteLogger))
+		logger.Info("Watching search", zap.String("site", site.Name), zap.String("url", search), zap.String("loader", kind))
+	}
+
+	return sources
+}
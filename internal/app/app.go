@@ -2,11 +2,19 @@ package app
 
 import (
 	"context"
+	"errors"
+	"jooble-parser/internal/artifacts"
 	"jooble-parser/internal/config"
 	"jooble-parser/internal/differ"
+	"jooble-parser/internal/enricher"
+	"jooble-parser/internal/filter"
 	downloader "jooble-parser/internal/loader"
-	htmlParser "jooble-parser/internal/parser"
+	"jooble-parser/internal/normalize"
+	"jooble-parser/internal/parser"
+	"jooble-parser/internal/service"
 	"jooble-parser/internal/signal"
+	src "jooble-parser/internal/source"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -15,66 +23,241 @@ import (
 type App struct {
 	cfg    *config.Config
 	logger *zap.Logger
-	url    string
 
-	loader downloader.HtmlLoader
-	parser *htmlParser.JobParser
-	differ differ.Differ
-	signal signal.UpdateSignal
+	sources    []src.JobSource
+	normalizer *normalize.Normalizer
+	enricher   enricher.Enricher
+	differ     differ.Differ
+	jobs       service.JobService
+	filter     filter.Filter
+	signal     signal.UpdateSignal
+
+	// one source is fetched and processed at a time, they share the browser
+	// and the storage
+	mu sync.Mutex
+}
+
+// watch is the state of the loop of one source.
+type watch struct {
+	source src.JobSource
+	logger *zap.Logger
+
+	retries int
+	backoff int
+	failure error
 }
 
 func New(cfg *config.Config,
 	logger *zap.Logger,
-	loader downloader.HtmlLoader,
-	parser *htmlParser.JobParser,
+	sources []src.JobSource,
+	normalizer *normalize.Normalizer,
+	enricher enricher.Enricher,
 	differ differ.Differ,
+	jobs service.JobService,
+	filter filter.Filter,
 	sign signal.UpdateSignal) *App {
 
 	return &App{
-		cfg:    cfg,
-		logger: logger,
-		loader: loader,
-		parser: parser,
-		url:    cfg.Parsing.Url,
-		differ: differ,
-		signal: sign,
+		cfg:        cfg,
+		logger:     logger,
+		sources:    sources,
+		normalizer: normalizer,
+		enricher:   enricher,
+		differ:     differ,
+		jobs:       jobs,
+		filter:     filter,
+		signal:     sign,
 	}
 }
 
+// Run watches every source in its own loop until ctx is done.
 func (app *App) Run(ctx context.Context) {
-	loader := app.loader
-	parser := app.parser
-	differ := app.differ
-	signal := app.signal
-	logger := app.logger
+	var wg sync.WaitGroup
+
+	for i, source := range app.sources {
+		w := &watch{
+			source: source,
+			logger: app.logger.With(zap.Int("source", i)),
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			app.watch(ctx, w)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (app *App) watch(ctx context.Context, w *watch) {
+	defer func() {
+		if err := w.source.Close(); err != nil {
+			w.logger.Error("source close error", zap.Error(err))
+		}
+	}()
 
 	for {
-		html, err := loader.Load(app.url, ctx)
-		if err != nil {
-			logger.Error("loader error", zap.Error(err))
-			continue
+		if ctx.Err() != nil {
+			return
 		}
 
-		jobs, err := parser.Parse(html)
-		if err != nil {
-			logger.Error("parser error", zap.Error(err))
-			continue
+		app.mu.Lock()
+		delay := app.poll(ctx, w)
+		app.mu.Unlock()
+
+		app.sleepFor(ctx, delay)
+	}
+}
+
+// poll fetches the source once, signals its new jobs and returns how long to
+// wait before the next fetch.
+func (app *App) poll(ctx context.Context, w *watch) time.Duration {
+	logger := w.logger
+
+	runCtx := artifacts.WithRunID(ctx, artifacts.NewRunID())
+	jobs, err := w.source.Fetch(runCtx)
+	if err != nil {
+		return app.onFetchError(w, err)
+	}
+	app.onFetchSuccess(w)
+
+	// the detail pages give the normalizer more to read
+	jobs = app.enricher.Enrich(ctx, jobs)
+	jobs = app.normalizer.Normalize(jobs)
+
+	new, err := app.differ.Check(jobs)
+	if err != nil {
+		logger.Error("differ error", zap.Error(err))
+		return app.cfg.Parsing.GetDelayDuration()
+	}
+
+	if len(new) > 0 {
+		app.logCities(logger)
+	}
+
+	if committer, ok := w.source.(src.Committer); ok {
+		if err := committer.Commit(); err != nil {
+			logger.Error("source commit error", zap.Error(err))
 		}
+	}
 
-		new, err := differ.Check(jobs)
-		if err != nil {
-			logger.Error("differ error", zap.Error(err))
-			continue
+	selected := app.filter.Apply(new)
+	if len(selected) < len(new) {
+		logger.Info("filtered out new jobs", zap.Int("new", len(new)), zap.Int("selected", len(selected)))
+	}
+
+	if err := app.signal.Signal(selected); err != nil {
+		logger.Error("update signal error", zap.Error(err))
+	}
+
+	return app.cfg.Parsing.GetDelayDuration()
+}
+
+// onFetchError decides how the loop of a source continues after a failed
+// fetch: timeouts are retried shortly, blocks and captchas back off
+// exponentially and alert the operator, a changed layout or a degraded parse
+// alerts and keeps the usual pace.
+func (app *App) onFetchError(w *watch, err error) time.Duration {
+	logger := w.logger
+	loaderCfg := &app.cfg.Loader
+	delay := app.cfg.Parsing.GetDelayDuration()
+
+	switch {
+	case errors.Is(err, src.ErrUnchanged):
+		logger.Info("search results unchanged")
+		app.onFetchSuccess(w)
+
+	case errors.Is(err, downloader.ErrEmptyResults):
+		logger.Info("search returned no results")
+		app.onFetchSuccess(w)
+
+	case errors.Is(err, downloader.ErrTimeout):
+		if w.retries < loaderCfg.GetRetries() {
+			w.retries++
+			logger.Warn("source timeout, retrying", zap.Int("attempt", w.retries), zap.Error(err), artifacts.Field(err))
+			return loaderCfg.GetRetryDelayDuration()
 		}
+		logger.Error("source timeout, retries exhausted", zap.Error(err), artifacts.Field(err))
+		w.retries = 0
+
+	case errors.Is(err, downloader.ErrBlocked), errors.Is(err, downloader.ErrCaptcha):
+		w.backoff++
+		delay = app.backoffDelay(w)
+		logger.Error("source blocked, backing off", zap.Duration("delay", delay), zap.Error(err), artifacts.Field(err))
+		app.alert(w, err)
+
+	case errors.Is(err, downloader.ErrLayoutChanged), errors.Is(err, parser.ErrDegraded):
+		logger.Error("source error", zap.Error(err), artifacts.Field(err))
+		app.alert(w, err)
+
+	default:
+		logger.Error("source error", zap.Error(err), artifacts.Field(err))
+	}
+
+	return delay
+}
+
+func (app *App) onFetchSuccess(w *watch) {
+	w.retries = 0
+	w.backoff = 0
+	w.failure = nil
+}
+
+func (app *App) backoffDelay(w *watch) time.Duration {
+	delay := app.cfg.Parsing.GetDelayDuration()
+	limit := app.cfg.Loader.GetMaxBackoffDuration()
 
-		if err := signal.Signal(new); err != nil {
-			logger.Error("update signal error", zap.Error(err))
+	for i := 1; i < w.backoff && delay < limit; i++ {
+		delay *= 2
+	}
+
+	return min(delay, limit)
+}
+
+// alert notifies the operator once per streak of failures of the same kind
+// of a source.
+func (app *App) alert(w *watch, err error) {
+	kinds := []error{
+		downloader.ErrBlocked,
+		downloader.ErrCaptcha,
+		downloader.ErrLayoutChanged,
+		parser.ErrDegraded,
+	}
+
+	for _, kind := range kinds {
+		if !errors.Is(err, kind) {
+			continue
+		}
+		if w.failure == kind {
+			return
 		}
+		w.failure = kind
+	}
 
-		app.Sleep()
+	if err := app.signal.Alert(err.Error()); err != nil {
+		w.logger.Error("alert signal error", zap.Error(err))
 	}
 }
 
-func (app *App) Sleep() {
-	time.Sleep(time.Second * app.cfg.ParsingConfig.Delay)
+// logCities logs how many of the stored jobs are in every city.
+func (app *App) logCities(logger *zap.Logger) {
+	counts, err := app.jobs.CountByCity()
+	if err != nil {
+		logger.Warn("Failed to count jobs by city", zap.Error(err))
+		return
+	}
+
+	if unknown, ok := counts[""]; ok {
+		delete(counts, "")
+		counts["unknown"] = unknown
+	}
+	logger.Info("Stored jobs by city", zap.Any("cities", counts))
+}
+
+func (app *App) sleepFor(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
 }
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"jooble-parser/internal/artifacts"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/differ"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/enricher"
+	"jooble-parser/internal/filter"
+	"jooble-parser/internal/loader"
+	"jooble-parser/internal/normalize"
+	"jooble-parser/internal/parser"
+	"jooble-parser/internal/service"
+	"jooble-parser/internal/sites"
+	"jooble-parser/internal/source"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const searchUrl = "https://ua.jooble.org/SearchResult?ukw=golang"
+
+// recordingSignal keeps the jobs and alerts it was sent.
+type recordingSignal struct {
+	signalled [][]domain.Job
+	alerts    []string
+}
+
+func (s *recordingSignal) Signal(jobs []domain.Job) error {
+	s.signalled = append(s.signalled, jobs)
+	return nil
+}
+
+func (s *recordingSignal) Alert(message string) error {
+	s.alerts = append(s.alerts, message)
+	return nil
+}
+
+// newReplayApp builds an app watching searchUrl on the pages recorded in
+// testdata, with a temporary database.
+func newReplayApp(t *testing.T) (*App, *watch, *recordingSignal) {
+	t.Helper()
+
+	logger := zap.NewNop()
+	cfg := &config.Config{}
+	cfg.DB.Path = filepath.Join(t.TempDir(), "data.db")
+	cfg.SetDefaults()
+
+	registry, err := sites.NewRegistry(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	site, err := registry.Lookup(searchUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := loader.NewReplayLoader("testdata", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobParser, err := parser.NewJobParser(logger, site.Spec, &cfg.Parsing.Health)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fingerprints, err := service.NewSqliteFingerprintService(cfg.DB.Path, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs, err := service.NewSqliteRepoService(cfg.DB.Path, cfg.DB.Limit, uint(cfg.DB.ClearingStep), logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobSource := source.NewHtmlSource(site, replay, jobParser, searchUrl, fingerprints, artifacts.NewNopStore(), logger)
+	sign := &recordingSignal{}
+	app := New(cfg,
+		logger,
+		[]source.JobSource{jobSource},
+		normalize.NewNormalizer(time.UTC),
+		enricher.NewNopEnricher(),
+		differ.NewDefaultDiffer(jobs),
+		jobs,
+		filter.NewJobFilter(&cfg.Filter),
+		sign)
+
+	return app, &watch{source: jobSource, logger: logger}, sign
+}
+
+func TestPollReplayed(t *testing.T) {
+	app, w, sign := newReplayApp(t)
+
+	// the first page, the same page again and the page with one job more
+	for range 3 {
+		app.poll(context.Background(), w)
+	}
+
+	if len(sign.alerts) != 0 {
+		t.Fatalf("got alerts %v", sign.alerts)
+	}
+	if len(sign.signalled) != 2 {
+		t.Fatalf("got %d signals, want none for the unchanged page", len(sign.signalled))
+	}
+
+	ids := func(jobs []domain.Job) []string {
+		result := make([]string, len(jobs))
+		for i, job := range jobs {
+			result[i] = job.ExternalID
+		}
+		return result
+	}
+	if got, want := ids(sign.signalled[0]), []string{"464575341174131141", "-4134916308710032189"}; !slices.Equal(got, want) {
+		t.Errorf("first signal = %v, want %v", got, want)
+	}
+	if got, want := ids(sign.signalled[1]), []string{"7730212955108862112"}; !slices.Equal(got, want) {
+		t.Errorf("second signal = %v, want only the new job %v", got, want)
+	}
+
+	job := sign.signalled[1][0]
+	if job.Source != sites.Jooble || job.Company != "Acme" || job.Location.City != "Kyiv" {
+		t.Errorf("new job = %q at %q in %q, want it normalized", job.Source, job.Company, job.Location.City)
+	}
+
+	cities, err := app.jobs.CountByCity()
+	if err != nil {
+		t.Fatalf("CountByCity() error = %v", err)
+	}
+	if cities["Kyiv"] != 3 || len(cities) != 1 {
+		t.Errorf("CountByCity() = %v, want the 3 jobs in Kyiv", cities)
+	}
+}
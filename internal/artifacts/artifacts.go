@@ -0,0 +1,65 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Store keeps files that help to investigate a failed run, grouped by run ID.
+type Store interface {
+	// Save stores data under name for the run of ctx and returns its path.
+	Save(ctx context.Context, name string, data []byte) (string, error)
+}
+
+// Error carries the directory holding the artifacts of a failed run.
+type Error struct {
+	Err error
+	Dir string
+}
+
+type runIDKey struct{}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap annotates err with the artifacts directory, if there is one.
+func Wrap(err error, dir string) error {
+	if err == nil || dir == "" {
+		return err
+	}
+	return &Error{Err: err, Dir: dir}
+}
+
+// Field returns the artifacts directory of err as a log field.
+func Field(err error) zap.Field {
+	var artifactsErr *Error
+	if errors.As(err, &artifactsErr) {
+		return zap.String("artifacts", artifactsErr.Dir)
+	}
+	return zap.Skip()
+}
+
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	rand.Read(suffix)
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+func RunID(ctx context.Context) string {
+	runID, _ := ctx.Value(runIDKey{}).(string)
+	return runID
+}
@@ -0,0 +1,139 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"jooble-parser/internal/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DirStore writes artifacts into one directory per run and removes the
+// oldest runs once there are more than maxRuns of them or they take more
+// than maxBytes.
+type DirStore struct {
+	mu       sync.Mutex
+	dir      string
+	maxRuns  int
+	maxBytes int64
+	logger   *zap.Logger
+}
+
+type runDir struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func NewDirStore(cfg *config.ArtifactsConfig, logger *zap.Logger) Store {
+	return &DirStore{
+		dir:      cfg.Dir,
+		maxRuns:  cfg.MaxRuns,
+		maxBytes: int64(cfg.MaxSize) << 20,
+		logger:   logger,
+	}
+}
+
+func (s *DirStore) Save(ctx context.Context, name string, data []byte) (string, error) {
+	runID := RunID(ctx)
+	if runID == "" {
+		runID = NewRunID()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := filepath.Join(s.dir, runID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create artifacts dir %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write artifact %s: %w", path, err)
+	}
+
+	if err := s.rotate(dir); err != nil {
+		s.logger.Warn("Failed to rotate artifacts", zap.Error(err))
+	}
+
+	return path, nil
+}
+
+// rotate removes the oldest runs over the limits, never the current one.
+func (s *DirStore) rotate(current string) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to list artifacts: %w", err)
+	}
+
+	var runs []runDir
+	var total int64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		run, err := statRun(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		runs = append(runs, run)
+		total += run.size
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].modTime.Before(runs[j].modTime)
+	})
+
+	count := len(runs)
+	for _, run := range runs {
+		if count <= s.maxRuns && total <= s.maxBytes {
+			break
+		}
+		if run.path == current {
+			continue
+		}
+
+		if err := os.RemoveAll(run.path); err != nil {
+			return fmt.Errorf("failed to remove artifacts %s: %w", run.path, err)
+		}
+		count--
+		total -= run.size
+		s.logger.Debug("Removed old artifacts", zap.String("path", run.path))
+	}
+
+	return nil
+}
+
+func statRun(path string) (runDir, error) {
+	run := runDir{path: path}
+
+	err := filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			run.size += info.Size()
+		}
+		if info.ModTime().After(run.modTime) {
+			run.modTime = info.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return run, fmt.Errorf("failed to stat artifacts %s: %w", path, err)
+	}
+
+	return run, nil
+}
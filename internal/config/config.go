@@ -2,7 +2,12 @@ package config
 
 import (
 	"fmt"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/normalize"
+	"net/url"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -10,11 +15,19 @@ import (
 
 type (
 	Config struct {
-		Log     LogConfig     `yaml:"log"`
-		DB      DBConfig      `yaml:"db"`
-		Chrome  ChromeConfig  `yaml:"chrome"`
-		Parsing ParsingConfig `yaml:"parsing"`
-		Signal  SignalConfig  `yaml:"signal"`
+		Log       LogConfig       `yaml:"log"`
+		DB        DBConfig        `yaml:"db"`
+		Loader    LoaderConfig    `yaml:"loader"`
+		Chrome    ChromeConfig    `yaml:"chrome"`
+		Http      HttpConfig      `yaml:"http"`
+		Api       ApiConfig       `yaml:"api"`
+		Rotation  RotationConfig  `yaml:"rotation"`
+		Parsing   ParsingConfig   `yaml:"parsing"`
+		Sites     []SiteConfig    `yaml:"sites"`
+		Enrich    EnrichConfig    `yaml:"enrich"`
+		Artifacts ArtifactsConfig `yaml:"artifacts"`
+		Filter    FilterConfig    `yaml:"filter"`
+		Signal    SignalConfig    `yaml:"signal"`
 	}
 
 	LogConfig struct {
@@ -33,14 +46,144 @@ type (
 		ClearingStep uint   `yaml:"clear_step"`
 	}
 
+	LoaderConfig struct {
+		Kind      string       `yaml:"kind"`       // chrome, http or replay
+		RecordDir string       `yaml:"record_dir"` // every loaded page is saved here when set
+		ReplayDir string       `yaml:"replay_dir"` // snapshots served by the replay loader
+		Paging    PagingConfig `yaml:"paging"`
+
+		Retries    *int `yaml:"retries"`     // immediate retries after a timeout, 0 disables them
+		RetryDelay int  `yaml:"retry_delay"` // in s
+		MaxBackoff int  `yaml:"max_backoff"` // in m, upper bound of the delay after a block or captcha
+	}
+
+	PagingConfig struct {
+		MaxPages     int    `yaml:"max_pages"`
+		MaxCards     int    `yaml:"max_cards"`     // 0 means no limit, required with more than 1 page
+		PageParam    string `yaml:"page_param"`    // query parameter used by the http loader
+		MoreSelector string `yaml:"more_selector"` // "show more" button clicked by the chrome loader
+	}
+
 	ChromeConfig struct {
-		ExePath        string `yaml:"exe_path"`
-		UserDataFolder string `yaml:"user_data_folder"`
+		RemoteUrl      string            `yaml:"remote_url"` // devtools endpoint of a running browser, replaces exe_path
+		ExePath        string            `yaml:"exe_path"`
+		UserDataFolder string            `yaml:"user_data_folder"`
+		Headless       bool              `yaml:"headless"`
+		Flags          map[string]string `yaml:"flags"`   // "true" and "false" switch a flag on and off
+		Timeout        int               `yaml:"timeout"` // in s
+		Waits          []ChromeWait      `yaml:"waits"`
+		Block          ChromeBlock       `yaml:"block"`
+		Session        ChromeSession     `yaml:"session"`
+	}
+
+	ChromeSession struct {
+		Policy      string `yaml:"policy"`       // ephemeral, persistent or rotate
+		RotateEvery int    `yaml:"rotate_every"` // loads between profile wipes of the rotate policy
+	}
+
+	// ChromeBlock lists the requests the chrome loader fails before they are
+	// sent. A list left out gets the defaults, an empty list blocks nothing.
+	ChromeBlock struct {
+		ResourceTypes []string `yaml:"resource_types"` // devtools resource types, e.g. Image, Font, Media
+		UrlPatterns   []string `yaml:"url_patterns"`   // '*' matches any run of characters, '?' one character
+	}
+
+	ChromeWait struct {
+		Selector string `yaml:"selector"`
+		Delay    int    `yaml:"delay"` // in ms, pause after the selector became visible
+	}
+
+	HttpConfig struct {
+		Timeout   int               `yaml:"timeout"` // in s
+		UserAgent string            `yaml:"user_agent"`
+		Headers   map[string]string `yaml:"headers"`
+	}
+
+	ApiConfig struct {
+		BaseUrl  string `yaml:"base_url"`
+		Key      string `yaml:"key"`
+		Keywords string `yaml:"keywords"`
+		Location string `yaml:"location"`
+		Timeout  int    `yaml:"timeout"` // in s
+	}
+
+	RotationConfig struct {
+		Proxies     []string         `yaml:"proxies"` // http, https or socks5 urls
+		Profiles    []BrowserProfile `yaml:"profiles"`
+		MaxFailures int              `yaml:"max_failures"` // failures in a row before a proxy is evicted
+		EvictFor    int              `yaml:"evict_for"`    // in m
+	}
+
+	BrowserProfile struct {
+		UserAgent string `yaml:"user_agent"`
+		Width     int    `yaml:"width"`
+		Height    int    `yaml:"height"`
 	}
 
 	ParsingConfig struct {
-		Url   string `yaml:"url"`
-		Delay int    `yaml:"delay"` // in m
+		Source     string       `yaml:"source"` // html, api or xhr
+		Url        string       `yaml:"url"`
+		Urls       []string     `yaml:"urls"`        // more search pages of the html source, on any known site
+		XhrPattern string       `yaml:"xhr_pattern"` // part of the search results request url
+		Selectors  string       `yaml:"selectors"`   // yaml or json selector spec, the built-in one when empty
+		Health     HealthConfig `yaml:"health"`
+		Delay      int          `yaml:"delay"`    // in m
+		Timezone   string       `yaml:"timezone"` // IANA name, relative posting dates are read in it
+	}
+
+	// SiteConfig adds a site the html source can watch or overrides the set
+	// fields of the built-in site with the same name.
+	SiteConfig struct {
+		Name         string       `yaml:"name"`          // namespace of the external ids of its jobs
+		Hosts        []string     `yaml:"hosts"`         // subdomains of a host belong to the site
+		Loader       string       `yaml:"loader"`        // chrome or http, loader.kind when empty
+		Waits        []ChromeWait `yaml:"waits"`         // chrome.waits when empty
+		PageParam    string       `yaml:"page_param"`    // loader.paging.page_param when empty
+		MoreSelector string       `yaml:"more_selector"` // loader.paging.more_selector when empty
+		Selectors    string       `yaml:"selectors"`     // yaml or json selector spec
+	}
+
+	// HealthConfig sets when the jobs of a parsed page are refused as degraded.
+	// The fields are pointers so an explicit 0, which turns a check off, is
+	// told apart from a missing value.
+	HealthConfig struct {
+		MinCards    *int     `yaml:"min_cards"`     // cards a page must contain
+		MinFillRate *float64 `yaml:"min_fill_rate"` // 0..1, share of cards a required field must be filled in
+	}
+
+	EnrichConfig struct {
+		Enabled     bool            `yaml:"enabled"`
+		Concurrency int             `yaml:"concurrency"`
+		Interval    int             `yaml:"interval"` // in ms, between requests to one host
+		Timeout     int             `yaml:"timeout"`  // in s
+		Selectors   DetailSelectors `yaml:"selectors"`
+	}
+
+	DetailSelectors struct {
+		Description  string `yaml:"description"`
+		Requirements string `yaml:"requirements"` // optional, a "Requirements" heading is searched otherwise
+		Employer     string `yaml:"employer"`
+		ApplyLink    string `yaml:"apply_link"`
+	}
+
+	ArtifactsConfig struct {
+		Enabled bool   `yaml:"enabled"`  // save a screenshot and the html of failed loads
+		Dir     string `yaml:"dir"`      // one subdirectory per run
+		MaxRuns int    `yaml:"max_runs"` // oldest runs are removed above this count
+		MaxSize int    `yaml:"max_size"` // in MB, oldest runs are removed above this size
+	}
+
+	// FilterConfig selects the new jobs that are signalled, all jobs are stored.
+	FilterConfig struct {
+		MinSalary     int64    `yaml:"min_salary"`     // per month, hourly and yearly pay is converted
+		Currency      string   `yaml:"currency"`       // UAH, USD or EUR, salaries in others are not compared
+		RequireSalary bool     `yaml:"require_salary"` // drop jobs whose salary can't be compared
+		MaxAge        int      `yaml:"max_age"`        // in h, older postings are dropped, 0 keeps all
+		Employment    []string `yaml:"employment"`     // full_time, part_time, contract or internship, any when empty
+		WorkModes     []string `yaml:"work_modes"`     // remote, hybrid or onsite, any when empty
+		Cities        []string `yaml:"cities"`         // in any language, remote jobs pass, any when empty
+		Countries     []string `yaml:"countries"`      // names or ISO codes, remote jobs pass, any when empty
+		SortBy        string   `yaml:"sort_by"`        // empty keeps the page order, salary puts the best paid first, date the newest
 	}
 
 	SignalConfig struct {
@@ -49,6 +192,39 @@ type (
 	}
 )
 
+const (
+	LoaderChrome = "chrome"
+	LoaderHttp   = "http"
+	LoaderReplay = "replay"
+)
+
+const (
+	SessionEphemeral  = "ephemeral"  // a temporary profile per load
+	SessionPersistent = "persistent" // cookies and consent survive loads and restarts
+	SessionRotate     = "rotate"     // the profile is wiped every rotate_every loads
+)
+
+const (
+	SortBySalary = "salary"
+	SortByDate   = "date"
+)
+
+const (
+	SourceHtml = "html"
+	SourceApi  = "api"
+	SourceXhr  = "xhr"
+)
+
+// blockableResourceTypes are the devtools resource types chrome.block accepts.
+// Documents are left out, blocking them would block the search page itself.
+var blockableResourceTypes = []string{
+	"Stylesheet", "Image", "Media", "Font", "Script", "TextTrack", "XHR", "Fetch",
+	"Prefetch", "EventSource", "WebSocket", "Manifest", "SignedExchange", "Ping",
+	"CSPViolationReport", "Preflight", "FedCM", "Other",
+}
+
+const defaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/141.0.0.0 Safari/537.36"
+
 func Load(configPath string) (*Config, error) {
 	if IsDocker() {
 		return LoadFromEnv()
@@ -75,15 +251,133 @@ func (c *Config) SetDefaults() {
 		c.DB.Path = "./db/data.db"
 	}
 	if c.DB.Limit == 0 {
-		c.DB.Limit = 50
+		c.DB.Limit = 500
 	}
 	if c.DB.ClearingStep == 0 {
 		c.DB.ClearingStep = 10
 	}
 
+	if c.Loader.Kind == "" {
+		c.Loader.Kind = LoaderChrome
+	}
+	if c.Loader.Retries == nil {
+		c.Loader.Retries = ptr(2)
+	}
+	if c.Loader.RetryDelay == 0 {
+		c.Loader.RetryDelay = 10
+	}
+	if c.Loader.MaxBackoff == 0 {
+		c.Loader.MaxBackoff = 60
+	}
+	if c.Loader.Paging.MaxPages == 0 {
+		c.Loader.Paging.MaxPages = 1
+	}
+	if c.Loader.Paging.PageParam == "" {
+		c.Loader.Paging.PageParam = "p"
+	}
+	if c.Loader.Paging.MoreSelector == "" {
+		c.Loader.Paging.MoreSelector = `button[data-test-name="_loadMoreButton"]`
+	}
+
+	if c.Chrome.Timeout == 0 {
+		c.Chrome.Timeout = 90
+	}
+	if len(c.Chrome.Waits) == 0 {
+		c.Chrome.Waits = []ChromeWait{
+			{Selector: "body", Delay: 3000},
+			{Selector: `div[data-test-name="_jobCard"]`, Delay: 2000},
+		}
+	}
+	if c.Chrome.Session.Policy == "" {
+		c.Chrome.Session.Policy = SessionPersistent
+	}
+	if c.Chrome.Session.RotateEvery == 0 {
+		c.Chrome.Session.RotateEvery = 20
+	}
+	if c.Chrome.Block.ResourceTypes == nil {
+		c.Chrome.Block.ResourceTypes = []string{"Image", "Media", "Font"}
+	}
+	if c.Chrome.Block.UrlPatterns == nil {
+		c.Chrome.Block.UrlPatterns = []string{
+			"*google-analytics.com*",
+			"*googletagmanager.com*",
+			"*doubleclick.net*",
+			"*googlesyndication.com*",
+			"*adservice.google.*",
+			"*connect.facebook.net*",
+			"*mc.yandex.*",
+			"*hotjar.com*",
+		}
+	}
+
+	if c.Http.Timeout == 0 {
+		c.Http.Timeout = 30
+	}
+	if c.Http.UserAgent == "" {
+		c.Http.UserAgent = defaultUserAgent
+	}
+
+	if c.Rotation.MaxFailures == 0 {
+		c.Rotation.MaxFailures = 3
+	}
+	if c.Rotation.EvictFor == 0 {
+		c.Rotation.EvictFor = 10
+	}
+
+	if c.Api.BaseUrl == "" {
+		c.Api.BaseUrl = "https://jooble.org/api/"
+	}
+	if c.Api.Timeout == 0 {
+		c.Api.Timeout = 30
+	}
+
+	if c.Parsing.Source == "" {
+		c.Parsing.Source = SourceHtml
+	}
+	if c.Parsing.XhrPattern == "" {
+		c.Parsing.XhrPattern = "/api/serp/jobs"
+	}
 	if c.Parsing.Delay == 0 {
 		c.Parsing.Delay = 1
 	}
+	if c.Parsing.Timezone == "" {
+		c.Parsing.Timezone = "Europe/Kyiv"
+	}
+	if c.Parsing.Health.MinCards == nil {
+		c.Parsing.Health.MinCards = ptr(1)
+	}
+	if c.Parsing.Health.MinFillRate == nil {
+		c.Parsing.Health.MinFillRate = ptr(0.8)
+	}
+
+	if c.Enrich.Concurrency == 0 {
+		c.Enrich.Concurrency = 2
+	}
+	if c.Enrich.Interval == 0 {
+		c.Enrich.Interval = 1000
+	}
+	if c.Enrich.Timeout == 0 {
+		c.Enrich.Timeout = 30
+	}
+	if c.Enrich.Selectors.Description == "" {
+		c.Enrich.Selectors.Description = `[data-test-name="_jobDescription"], [itemprop="description"]`
+	}
+	if c.Enrich.Selectors.Employer == "" {
+		c.Enrich.Selectors.Employer = `[data-test-name="_companyInfo"], [itemprop="hiringOrganization"]`
+	}
+	if c.Enrich.Selectors.ApplyLink == "" {
+		c.Enrich.Selectors.ApplyLink = `a[data-test-name="_applyButton"]`
+	}
+
+	if c.Artifacts.Dir == "" {
+		c.Artifacts.Dir = "./artifacts"
+	}
+	if c.Artifacts.MaxRuns == 0 {
+		c.Artifacts.MaxRuns = 20
+	}
+	if c.Artifacts.MaxSize == 0 {
+		c.Artifacts.MaxSize = 100
+	}
 }
 
 func (c *Config) Validate() error {
@@ -104,29 +398,441 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("db.clearing_step is required")
 	}
 
+	if retries := c.Loader.GetRetries(); retries < 0 {
+		return fmt.Errorf("loader.retries must not be negative, got %d", retries)
+	}
+	if c.Loader.RetryDelay < 0 {
+		return fmt.Errorf("loader.retry_delay must not be negative, got %d", c.Loader.RetryDelay)
+	}
+	if c.Loader.MaxBackoff < 0 {
+		return fmt.Errorf("loader.max_backoff must not be negative, got %d", c.Loader.MaxBackoff)
+	}
+
+	switch c.Parsing.Source {
+	case "", SourceHtml:
+		if err := c.validateLoader(); err != nil {
+			return err
+		}
+		if len(c.Parsing.SearchUrls()) == 0 {
+			return fmt.Errorf("parsing.url or parsing.urls is required")
+		}
+		for i, search := range c.Parsing.Urls {
+			if u, err := url.Parse(search); err != nil || u.Host == "" {
+				return fmt.Errorf("parsing.urls[%d] must be an absolute url, got %q", i, search)
+			}
+		}
+		if err := c.validateSites(); err != nil {
+			return err
+		}
+		// jobs evicted from the db would be sent again as new ones, paging is
+		// only bounded by max_cards
+		if c.Loader.Paging.MaxPages > 1 && c.Loader.Paging.MaxCards == 0 {
+			return fmt.Errorf("loader.paging.max_cards is required when loader.paging.max_pages is above 1")
+		}
+		if cards := c.Loader.Paging.MaxCards * len(c.Parsing.SearchUrls()); cards > int(c.DB.Limit) {
+			return fmt.Errorf("db.limit must hold the %d cards loader.paging.max_cards allows for %d searches, got %d",
+				cards, len(c.Parsing.SearchUrls()), c.DB.Limit)
+		}
+		if c.Parsing.Selectors != "" {
+			if _, err := os.Stat(c.Parsing.Selectors); os.IsNotExist(err) {
+				return fmt.Errorf("parsing.selectors does not exist: %s", c.Parsing.Selectors)
+			}
+		}
+	case SourceApi:
+		if err := c.validateApi(); err != nil {
+			return err
+		}
+	case SourceXhr:
+		if c.Loader.Kind != "" && c.Loader.Kind != LoaderChrome {
+			return fmt.Errorf("parsing.source %q requires loader.kind %q", SourceXhr, LoaderChrome)
+		}
+		if err := c.validateLoader(); err != nil {
+			return err
+		}
+		if c.Parsing.Url == "" {
+			return fmt.Errorf("parsing.url is required")
+		}
+	default:
+		return fmt.Errorf("parsing.source must be %q, %q or %q, got %q",
+			SourceHtml, SourceApi, SourceXhr, c.Parsing.Source)
+	}
+
+	if c.Parsing.Source != "" && c.Parsing.Source != SourceHtml && len(c.Parsing.Urls) > 0 {
+		return fmt.Errorf("parsing.urls requires parsing.source %q", SourceHtml)
+	}
+
+	if c.Parsing.Delay < 1 {
+		return fmt.Errorf("parsing.delay must be at least 1 minute, got %d", c.Parsing.Delay)
+	}
+	if c.Parsing.Timezone != "" {
+		if _, err := time.LoadLocation(c.Parsing.Timezone); err != nil {
+			return fmt.Errorf("parsing.timezone is invalid: %w", err)
+		}
+	}
+	if minCards := c.Parsing.Health.GetMinCards(); minCards < 0 {
+		return fmt.Errorf("parsing.health.min_cards must not be negative, got %d", minCards)
+	}
+	if minFillRate := c.Parsing.Health.GetMinFillRate(); minFillRate < 0 || minFillRate > 1 {
+		return fmt.Errorf("parsing.health.min_fill_rate must be between 0 and 1, got %g", minFillRate)
+	}
+
+	if c.Enrich.Concurrency < 0 {
+		return fmt.Errorf("enrich.concurrency must not be negative, got %d", c.Enrich.Concurrency)
+	}
+	if c.Enrich.Interval < 0 {
+		return fmt.Errorf("enrich.interval must not be negative, got %d", c.Enrich.Interval)
+	}
+	if c.Enrich.Timeout < 0 {
+		return fmt.Errorf("enrich.timeout must not be negative, got %d", c.Enrich.Timeout)
+	}
+
+	if c.Filter.MinSalary < 0 {
+		return fmt.Errorf("filter.min_salary must not be negative, got %d", c.Filter.MinSalary)
+	}
+	switch c.Filter.Currency {
+	case "", domain.CurrencyUAH, domain.CurrencyUSD, domain.CurrencyEUR:
+	default:
+		return fmt.Errorf("filter.currency must be %q, %q or %q, got %q",
+			domain.CurrencyUAH, domain.CurrencyUSD, domain.CurrencyEUR, c.Filter.Currency)
+	}
+	for i, employment := range c.Filter.Employment {
+		if !slices.Contains(domain.EmploymentTypes, domain.EmploymentType(employment)) {
+			return fmt.Errorf("filter.employment[%d] must be one of %v, got %q", i, domain.EmploymentTypes, employment)
+		}
+	}
+	for i, mode := range c.Filter.WorkModes {
+		if !slices.Contains(domain.WorkModes, domain.WorkMode(mode)) {
+			return fmt.Errorf("filter.work_modes[%d] must be one of %v, got %q", i, domain.WorkModes, mode)
+		}
+	}
+	for i, city := range c.Filter.Cities {
+		if _, ok := normalize.CanonicalCity(city); !ok {
+			return fmt.Errorf("filter.cities[%d] is not a known city, got %q", i, city)
+		}
+	}
+	for i, country := range c.Filter.Countries {
+		if _, ok := normalize.CanonicalCountry(country); !ok {
+			return fmt.Errorf("filter.countries[%d] is not a known country, got %q", i, country)
+		}
+	}
+	if c.Filter.MaxAge < 0 {
+		return fmt.Errorf("filter.max_age must not be negative, got %d", c.Filter.MaxAge)
+	}
+	switch c.Filter.SortBy {
+	case "", SortBySalary, SortByDate:
+	default:
+		return fmt.Errorf("filter.sort_by must be empty, %q or %q, got %q", SortBySalary, SortByDate, c.Filter.SortBy)
+	}
+	// amounts are not converted between currencies
+	if c.Filter.Currency == "" && (c.Filter.MinSalary > 0 || c.Filter.SortBy == SortBySalary) {
+		return fmt.Errorf("filter.currency is required to compare salaries by filter.min_salary or filter.sort_by")
+	}
+
+	if c.Artifacts.MaxRuns < 0 {
+		return fmt.Errorf("artifacts.max_runs must not be negative, got %d", c.Artifacts.MaxRuns)
+	}
+	if c.Artifacts.MaxSize < 0 {
+		return fmt.Errorf("artifacts.max_size must not be negative, got %d", c.Artifacts.MaxSize)
+	}
+
+	return nil
+}
+
+// validateChromeBrowser checks how the browser is reached: a remote endpoint,
+// or a local executable with its own profile folder.
+func (c *Config) validateChromeBrowser() error {
+	if c.Chrome.RemoteUrl != "" {
+		remote, err := url.Parse(c.Chrome.RemoteUrl)
+		if err != nil {
+			return fmt.Errorf("chrome.remote_url is invalid: %w", err)
+		}
+		switch remote.Scheme {
+		case "ws", "wss", "http", "https":
+		default:
+			return fmt.Errorf("chrome.remote_url must be a ws, wss, http or https url, got %q", c.Chrome.RemoteUrl)
+		}
+		if remote.Host == "" {
+			return fmt.Errorf("chrome.remote_url must contain a host, got %q", c.Chrome.RemoteUrl)
+		}
+		return nil
+	}
+
 	if c.Chrome.ExePath == "" {
-		return fmt.Errorf("chrome.exe_path is required")
+		return fmt.Errorf("chrome.exe_path is required when chrome.remote_url is not set")
 	}
 	if _, err := os.Stat(c.Chrome.ExePath); os.IsNotExist(err) {
 		return fmt.Errorf("chrome.exe_path does not exist: %s", c.Chrome.ExePath)
 	}
 	if c.Chrome.UserDataFolder == "" {
-		return fmt.Errorf("chrome.user_data_folder is required")
+		return fmt.Errorf("chrome.user_data_folder is required when chrome.remote_url is not set")
 	}
 
-	if c.Parsing.Url == "" {
-		return fmt.Errorf("parsing.url is required")
+	return nil
+}
+
+func (c *Config) validateSites() error {
+	names := make(map[string]bool)
+
+	for i, site := range c.Sites {
+		if site.Name == "" {
+			return fmt.Errorf("sites[%d].name is required", i)
+		}
+		if names[site.Name] {
+			return fmt.Errorf("sites[%d].name %q is used twice", i, site.Name)
+		}
+		names[site.Name] = true
+
+		for j, host := range site.Hosts {
+			if host == "" || strings.ContainsAny(host, "/:") {
+				return fmt.Errorf("sites[%d].hosts[%d] must be a host name, got %q", i, j, host)
+			}
+		}
+		switch site.Loader {
+		case "", LoaderHttp:
+		case LoaderChrome:
+			// loader.kind chrome was validated with the loader, replayed
+			// pages need no browser
+			if c.Loader.Kind == LoaderHttp {
+				if err := c.validateChrome(); err != nil {
+					return fmt.Errorf("sites[%d] uses chrome: %w", i, err)
+				}
+			}
+		default:
+			return fmt.Errorf("sites[%d].loader must be empty, %q or %q, got %q", i, LoaderChrome, LoaderHttp, site.Loader)
+		}
+		for j, wait := range site.Waits {
+			if wait.Selector == "" {
+				return fmt.Errorf("sites[%d].waits[%d].selector is required", i, j)
+			}
+			if wait.Delay < 0 {
+				return fmt.Errorf("sites[%d].waits[%d].delay must not be negative, got %d", i, j, wait.Delay)
+			}
+		}
+		if site.Selectors != "" {
+			if _, err := os.Stat(site.Selectors); os.IsNotExist(err) {
+				return fmt.Errorf("sites[%d].selectors does not exist: %s", i, site.Selectors)
+			}
+		}
 	}
-	if c.Parsing.Delay < 1 {
-		return fmt.Errorf("parsing.delay must be at least 1 minute, got %d", c.Parsing.Delay)
+
+	return nil
+}
+
+func (c *Config) validateLoader() error {
+	if c.Loader.Paging.MaxPages < 0 {
+		return fmt.Errorf("loader.paging.max_pages must not be negative, got %d", c.Loader.Paging.MaxPages)
+	}
+	if c.Loader.Paging.MaxCards < 0 {
+		return fmt.Errorf("loader.paging.max_cards must not be negative, got %d", c.Loader.Paging.MaxCards)
+	}
+
+	if err := c.validateRotation(); err != nil {
+		return err
+	}
+
+	// built-in boards are loaded over http whatever loader.kind is
+	if c.Http.Timeout < 0 {
+		return fmt.Errorf("http.timeout must not be negative, got %d", c.Http.Timeout)
+	}
+
+	switch c.Loader.Kind {
+	case "", LoaderChrome:
+		if err := c.validateChrome(); err != nil {
+			return err
+		}
+	case LoaderHttp:
+	case LoaderReplay:
+		if c.Loader.ReplayDir == "" {
+			return fmt.Errorf("loader.replay_dir is required when loader.kind is %q", LoaderReplay)
+		}
+		if _, err := os.Stat(c.Loader.ReplayDir); os.IsNotExist(err) {
+			return fmt.Errorf("loader.replay_dir does not exist: %s", c.Loader.ReplayDir)
+		}
+	default:
+		return fmt.Errorf("loader.kind must be %q, %q or %q, got %q",
+			LoaderChrome, LoaderHttp, LoaderReplay, c.Loader.Kind)
 	}
 
 	return nil
 }
+
+// validateChrome checks the browser and how pages are loaded in it.
+func (c *Config) validateChrome() error {
+	if err := c.validateChromeBrowser(); err != nil {
+		return err
+	}
+	if c.Chrome.Timeout < 0 {
+		return fmt.Errorf("chrome.timeout must not be negative, got %d", c.Chrome.Timeout)
+	}
+	for name := range c.Chrome.Flags {
+		if name == "" {
+			return fmt.Errorf("chrome.flags must not contain an empty flag name")
+		}
+	}
+	for i, wait := range c.Chrome.Waits {
+		if wait.Selector == "" {
+			return fmt.Errorf("chrome.waits[%d].selector is required", i)
+		}
+		if wait.Delay < 0 {
+			return fmt.Errorf("chrome.waits[%d].delay must not be negative, got %d", i, wait.Delay)
+		}
+	}
+	switch c.Chrome.Session.Policy {
+	case "", SessionEphemeral, SessionPersistent, SessionRotate:
+	default:
+		return fmt.Errorf("chrome.session.policy must be %q, %q or %q, got %q",
+			SessionEphemeral, SessionPersistent, SessionRotate, c.Chrome.Session.Policy)
+	}
+	if c.Chrome.Session.RotateEvery < 0 {
+		return fmt.Errorf("chrome.session.rotate_every must not be negative, got %d", c.Chrome.Session.RotateEvery)
+	}
+	for i, resourceType := range c.Chrome.Block.ResourceTypes {
+		if !slices.Contains(blockableResourceTypes, resourceType) {
+			return fmt.Errorf("chrome.block.resource_types[%d] must be one of %v, got %q",
+				i, blockableResourceTypes, resourceType)
+		}
+	}
+	for i, pattern := range c.Chrome.Block.UrlPatterns {
+		if pattern == "" {
+			return fmt.Errorf("chrome.block.url_patterns[%d] must not be empty", i)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) validateRotation() error {
+	for i, raw := range c.Rotation.Proxies {
+		proxy, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("rotation.proxies[%d] is not a valid url: %w", i, err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("rotation.proxies[%d] must use http, https or socks5, got %q", i, proxy.Scheme)
+		}
+		if proxy.Host == "" {
+			return fmt.Errorf("rotation.proxies[%d] has no host", i)
+		}
+	}
+	for i, profile := range c.Rotation.Profiles {
+		if profile.UserAgent == "" {
+			return fmt.Errorf("rotation.profiles[%d].user_agent is required", i)
+		}
+		if profile.Width < 0 || profile.Height < 0 {
+			return fmt.Errorf("rotation.profiles[%d] viewport must not be negative", i)
+		}
+	}
+	if c.Rotation.MaxFailures < 0 {
+		return fmt.Errorf("rotation.max_failures must not be negative, got %d", c.Rotation.MaxFailures)
+	}
+	if c.Rotation.EvictFor < 0 {
+		return fmt.Errorf("rotation.evict_for must not be negative, got %d", c.Rotation.EvictFor)
+	}
+
+	return nil
+}
+
+func (c *Config) validateApi() error {
+	if c.Api.Key == "" {
+		return fmt.Errorf("api.key is required when parsing.source is %q", SourceApi)
+	}
+	if c.Api.Keywords == "" {
+		return fmt.Errorf("api.keywords is required when parsing.source is %q", SourceApi)
+	}
+	if c.Api.Timeout < 0 {
+		return fmt.Errorf("api.timeout must not be negative, got %d", c.Api.Timeout)
+	}
+
+	return nil
+}
+
 func (p *ParsingConfig) GetDelayDuration() time.Duration {
 	return time.Duration(p.Delay) * time.Minute
 }
 
+func (l *LoaderConfig) GetRetries() int {
+	if l.Retries == nil {
+		return 0
+	}
+	return *l.Retries
+}
+
+func (l *LoaderConfig) GetRetryDelayDuration() time.Duration {
+	return time.Duration(l.RetryDelay) * time.Second
+}
+
+func (l *LoaderConfig) GetMaxBackoffDuration() time.Duration {
+	return time.Duration(l.MaxBackoff) * time.Minute
+}
+
+func (c *ChromeConfig) GetTimeoutDuration() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
+}
+
+func (w *ChromeWait) GetDelayDuration() time.Duration {
+	return time.Duration(w.Delay) * time.Millisecond
+}
+
+func (h *HttpConfig) GetTimeoutDuration() time.Duration {
+	return time.Duration(h.Timeout) * time.Second
+}
+
+func (r *RotationConfig) GetEvictForDuration() time.Duration {
+	return time.Duration(r.EvictFor) * time.Minute
+}
+
+func (a *ApiConfig) GetTimeoutDuration() time.Duration {
+	return time.Duration(a.Timeout) * time.Second
+}
+
+func (h *HealthConfig) GetMinCards() int {
+	if h.MinCards == nil {
+		return 0
+	}
+	return *h.MinCards
+}
+
+func (h *HealthConfig) GetMinFillRate() float64 {
+	if h.MinFillRate == nil {
+		return 0
+	}
+	return *h.MinFillRate
+}
+
+// SearchUrls lists the search pages watched by the html source, url first.
+func (p *ParsingConfig) SearchUrls() []string {
+	var urls []string
+	for _, search := range append([]string{p.Url}, p.Urls...) {
+		if search != "" && !slices.Contains(urls, search) {
+			urls = append(urls, search)
+		}
+	}
+	return urls
+}
+
+// GetLocation falls back to UTC for a timezone that failed validation.
+func (p *ParsingConfig) GetLocation() *time.Location {
+	location, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (f *FilterConfig) GetMaxAgeDuration() time.Duration {
+	return time.Duration(f.MaxAge) * time.Hour
+}
+
+func (e *EnrichConfig) GetIntervalDuration() time.Duration {
+	return time.Duration(e.Interval) * time.Millisecond
+}
+
+func (e *EnrichConfig) GetTimeoutDuration() time.Duration {
+	return time.Duration(e.Timeout) * time.Second
+}
+
 func (c *Config) Save(configPath string) error {
 	data, err := yaml.Marshal(c)
 	if err != nil {
@@ -139,3 +845,7 @@ func (c *Config) Save(configPath string) error {
 
 	return nil
 }
+
+func ptr[T any](value T) *T {
+	return &value
+}
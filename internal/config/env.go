@@ -7,8 +7,6 @@ import (
 )
 
 func LoadFromEnv() (*Config, error) {
-	panic("parsing signal config group not implemented")
-
 	cfg := &Config{
 		Log: LogConfig{
 			LogToFile:  getEnvAsBool("LOG_TO_FILE", true),
@@ -21,16 +19,93 @@ func LoadFromEnv() (*Config, error) {
 		},
 		DB: DBConfig{
 			Path:         getEnv("DB_PATH", "./db/data.db"),
-			Limit:        getEnvAsUint("DB_LIMIT", 50),
+			Limit:        getEnvAsUint("DB_LIMIT", 500),
 			ClearingStep: getEnvAsUint("DB_CLEARING_STEP", 10),
 		},
+		Loader: LoaderConfig{
+			Kind:       getEnv("LOADER_KIND", LoaderChrome),
+			RecordDir:  getEnv("LOADER_RECORD_DIR", ""),
+			ReplayDir:  getEnv("LOADER_REPLAY_DIR", ""),
+			Retries:    ptr(getEnvAsInt("LOADER_RETRIES", 2)),
+			RetryDelay: getEnvAsInt("LOADER_RETRY_DELAY", 10),
+			MaxBackoff: getEnvAsInt("LOADER_MAX_BACKOFF", 60),
+			Paging: PagingConfig{
+				MaxPages:     getEnvAsInt("LOADER_MAX_PAGES", 1),
+				MaxCards:     getEnvAsInt("LOADER_MAX_CARDS", 0),
+				PageParam:    getEnv("LOADER_PAGE_PARAM", "p"),
+				MoreSelector: getEnv("LOADER_MORE_SELECTOR", `button[data-test-name="_loadMoreButton"]`),
+			},
+		},
 		Chrome: ChromeConfig{
+			RemoteUrl:      getEnv("CHROME_REMOTE_URL", ""),
 			ExePath:        getEnv("CHROME_EXE_PATH", ""),
 			UserDataFolder: getEnv("CHROME_USER_DATA_FOLDER", ""),
+			Headless:       getEnvAsBool("CHROME_HEADLESS", false),
+			Timeout:        getEnvAsInt("CHROME_TIMEOUT", 90),
+			Session: ChromeSession{
+				Policy:      getEnv("CHROME_SESSION_POLICY", SessionPersistent),
+				RotateEvery: getEnvAsInt("CHROME_SESSION_ROTATE_EVERY", 20),
+			},
+			Block: ChromeBlock{
+				ResourceTypes: getEnvAsList("CHROME_BLOCK_RESOURCE_TYPES"),
+				UrlPatterns:   getEnvAsList("CHROME_BLOCK_URL_PATTERNS"),
+			},
+		},
+		Http: HttpConfig{
+			Timeout:   getEnvAsInt("HTTP_TIMEOUT", 30),
+			UserAgent: getEnv("HTTP_USER_AGENT", defaultUserAgent),
+		},
+		Rotation: RotationConfig{
+			Proxies:     getEnvAsList("ROTATION_PROXIES"),
+			MaxFailures: getEnvAsInt("ROTATION_MAX_FAILURES", 3),
+			EvictFor:    getEnvAsInt("ROTATION_EVICT_FOR", 10),
+		},
+		Api: ApiConfig{
+			BaseUrl:  getEnv("API_BASE_URL", "https://jooble.org/api/"),
+			Key:      getEnv("API_KEY", ""),
+			Keywords: getEnv("API_KEYWORDS", ""),
+			Location: getEnv("API_LOCATION", ""),
+			Timeout:  getEnvAsInt("API_TIMEOUT", 30),
 		},
 		Parsing: ParsingConfig{
-			Url:   getEnv("PARSING_URL", ""),
-			Delay: getEnvAsInt("PARSING_DELAY", 1),
+			Source:     getEnv("PARSING_SOURCE", SourceHtml),
+			Url:        getEnv("PARSING_URL", ""),
+			Urls:       getEnvAsList("PARSING_URLS"),
+			XhrPattern: getEnv("PARSING_XHR_PATTERN", "/api/serp/jobs"),
+			Selectors:  getEnv("PARSING_SELECTORS", ""),
+			Delay:      getEnvAsInt("PARSING_DELAY", 1),
+			Timezone:   getEnv("PARSING_TIMEZONE", "Europe/Kyiv"),
+			Health: HealthConfig{
+				MinCards:    ptr(getEnvAsInt("PARSING_HEALTH_MIN_CARDS", 1)),
+				MinFillRate: ptr(getEnvAsFloat("PARSING_HEALTH_MIN_FILL_RATE", 0.8)),
+			},
+		},
+		Enrich: EnrichConfig{
+			Enabled:     getEnvAsBool("ENRICH_ENABLED", false),
+			Concurrency: getEnvAsInt("ENRICH_CONCURRENCY", 2),
+			Interval:    getEnvAsInt("ENRICH_INTERVAL", 1000),
+			Timeout:     getEnvAsInt("ENRICH_TIMEOUT", 30),
+		},
+		Filter: FilterConfig{
+			MinSalary:     getEnvAsInt64("FILTER_MIN_SALARY", 0),
+			Currency:      getEnv("FILTER_CURRENCY", ""),
+			RequireSalary: getEnvAsBool("FILTER_REQUIRE_SALARY", false),
+			MaxAge:        getEnvAsInt("FILTER_MAX_AGE", 0),
+			Employment:    getEnvAsList("FILTER_EMPLOYMENT"),
+			WorkModes:     getEnvAsList("FILTER_WORK_MODES"),
+			Cities:        getEnvAsList("FILTER_CITIES"),
+			Countries:     getEnvAsList("FILTER_COUNTRIES"),
+			SortBy:        getEnv("FILTER_SORT_BY", ""),
+		},
+		Artifacts: ArtifactsConfig{
+			Enabled: getEnvAsBool("ARTIFACTS_ENABLED", false),
+			Dir:     getEnv("ARTIFACTS_DIR", "./artifacts"),
+			MaxRuns: getEnvAsInt("ARTIFACTS_MAX_RUNS", 20),
+			MaxSize: getEnvAsInt("ARTIFACTS_MAX_SIZE", 100),
+		},
+		Signal: SignalConfig{
+			Token:      getEnv("SIGNAL_TOKEN", ""),
+			CustomerId: getEnvAsInt64("SIGNAL_CUSTOMER_ID", 0),
 		},
 	}
 
@@ -38,6 +113,8 @@ func LoadFromEnv() (*Config, error) {
 		return nil, err
 	}
 
+	cfg.SetDefaults()
+
 	return cfg, nil
 }
 
@@ -62,6 +139,34 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+func getEnvAsInt64(key string, defaultValue int64) int64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
+
 func getEnvAsUint(key string, defaultValue uint) uint {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
@@ -74,6 +179,16 @@ func getEnvAsUint(key string, defaultValue uint) uint {
 	return uint(value)
 }
 
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
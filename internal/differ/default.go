@@ -48,7 +48,7 @@ func (d *SqliteDiffer) Check(parsed []domain.Job) ([]domain.Job, error) {
 
 func checkExisting(job domain.Job, existing []domain.Job) bool {
 	for _, existingJob := range existing {
-		if job.ExternalID == existingJob.ExternalID {
+		if job.Source == existingJob.Source && job.ExternalID == existingJob.ExternalID {
 			return true
 		}
 	}
@@ -0,0 +1,25 @@
+package domain
+
+// EmploymentType is the kind of contract of a job, empty when unknown.
+type EmploymentType string
+
+const (
+	EmploymentFullTime   EmploymentType = "full_time"
+	EmploymentPartTime   EmploymentType = "part_time"
+	EmploymentContract   EmploymentType = "contract"
+	EmploymentInternship EmploymentType = "internship"
+)
+
+// WorkMode is where a job is done, empty when unknown.
+type WorkMode string
+
+const (
+	WorkModeRemote WorkMode = "remote"
+	WorkModeHybrid WorkMode = "hybrid"
+	WorkModeOnsite WorkMode = "onsite"
+)
+
+var (
+	EmploymentTypes = []EmploymentType{EmploymentFullTime, EmploymentPartTime, EmploymentContract, EmploymentInternship}
+	WorkModes       = []WorkMode{WorkModeRemote, WorkModeHybrid, WorkModeOnsite}
+)
@@ -1,10 +1,14 @@
 package domain
 
-import "strconv"
+import (
+	"strconv"
+	"time"
+)
 
 type Job struct {
 	ID          int64    `json:"id"`
-	ExternalID  string   `json:"external_id"`
+	Source      string   `json:"source"`      // site the job was found on
+	ExternalID  string   `json:"external_id"` // unique within the source
 	Title       string   `json:"title"`
 	Company     string   `json:"company"`
 	City        string   `json:"city"`
@@ -14,6 +18,18 @@ type Job struct {
 	WorkType    string   `json:"work_type"`
 	Date        string   `json:"date"`
 	Tags        []string `json:"tags"`
+
+	Pay        Salary         `json:"pay"`        // parsed from Salary
+	PostedAt   time.Time      `json:"posted_at"`  // parsed from Date, zero when unknown
+	Employment EmploymentType `json:"employment"` // parsed from WorkType, Tags and the descriptions
+	WorkMode   WorkMode       `json:"work_mode"`  // parsed from WorkType, Tags, City and the descriptions
+	Location   Location       `json:"location"`   // parsed from City, Tags and the descriptions
+
+	// filled from the job detail page
+	FullDescription string `json:"full_description"`
+	Requirements    string `json:"requirements"`
+	EmployerInfo    string `json:"employer_info"`
+	OriginalURL     string `json:"original_url"`
 }
 
 func (job Job) GetId() (int64, error) {
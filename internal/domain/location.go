@@ -0,0 +1,11 @@
+package domain
+
+// Location is where a job is done. City, Region and Country hold canonical
+// english names, Country its ISO 3166 code. Empty fields are unknown.
+type Location struct {
+	City       string `json:"city"`
+	Region     string `json:"region"`
+	Country    string `json:"country"`
+	Remote     bool   `json:"remote"`
+	Relocation bool   `json:"relocation"` // relocation is offered
+}
@@ -0,0 +1,53 @@
+package domain
+
+const (
+	CurrencyUAH = "UAH"
+	CurrencyUSD = "USD"
+	CurrencyEUR = "EUR"
+)
+
+const (
+	PeriodHour  = "hour"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+const (
+	BasisGross = "gross"
+	BasisNet   = "net"
+)
+
+// Salary is the pay parsed from the salary text or structured data of a job.
+// Zero values are not stated.
+type Salary struct {
+	Min      int64  `json:"min"`
+	Max      int64  `json:"max"`
+	Currency string `json:"currency"` // UAH, USD or EUR, any ISO 4217 code in structured data
+	Period   string `json:"period"`   // hour, month or year
+	Basis    string `json:"basis"`    // gross or net
+}
+
+// hoursPerMonth is used to compare hourly rates with monthly salaries.
+const hoursPerMonth = 168
+
+func (s Salary) Known() bool {
+	return s.Min > 0 || s.Max > 0
+}
+
+// Monthly returns the upper bound of the pay per month, or the lower one when
+// there is no upper bound. A salary without a period is taken as monthly.
+func (s Salary) Monthly() int64 {
+	amount := s.Max
+	if amount == 0 {
+		amount = s.Min
+	}
+
+	switch s.Period {
+	case PeriodHour:
+		return amount * hoursPerMonth
+	case PeriodYear:
+		return amount / 12
+	default:
+		return amount
+	}
+}
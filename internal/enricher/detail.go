@@ -0,0 +1,232 @@
+package enricher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/parser"
+	"jooble-parser/internal/service"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"go.uber.org/zap"
+)
+
+// DetailEnricher visits the page of every job that is not stored yet and
+// fills the fields only the detail page has.
+type DetailEnricher struct {
+	client      *http.Client
+	userAgent   string
+	jobs        service.JobService
+	selectors   config.DetailSelectors
+	concurrency int
+	limiter     *hostLimiter
+	logger      *zap.Logger
+}
+
+var requirementsHeading = regexp.MustCompile(`(?i)^(вимоги|требования|requirements|що ми очікуємо|что мы ожидаем|what we expect)`)
+
+func NewDetailEnricher(cfg *config.Config, jobs service.JobService, logger *zap.Logger) Enricher {
+	return &DetailEnricher{
+		client: &http.Client{
+			Timeout: cfg.Enrich.GetTimeoutDuration(),
+		},
+		userAgent:   cfg.Http.UserAgent,
+		jobs:        jobs,
+		selectors:   cfg.Enrich.Selectors,
+		concurrency: cfg.Enrich.Concurrency,
+		limiter:     newHostLimiter(cfg.Enrich.GetIntervalDuration()),
+		logger:      logger,
+	}
+}
+
+func (e *DetailEnricher) Enrich(ctx context.Context, jobs []domain.Job) []domain.Job {
+	sem := make(chan struct{}, e.concurrency)
+	var wg sync.WaitGroup
+
+	for i := range jobs {
+		job := &jobs[i]
+		if job.Link == "" {
+			continue
+		}
+
+		exists, err := e.jobs.JobExists(job.Source, job.ExternalID)
+		if err != nil {
+			e.logger.Warn("Failed to check job existence",
+				zap.String("source", job.Source), zap.String("external_id", job.ExternalID), zap.Error(err))
+			continue
+		}
+		if exists {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			if err := e.enrich(ctx, job); err != nil {
+				e.logger.Warn("Failed to enrich job", zap.String("link", job.Link), zap.Error(err))
+			}
+		}()
+	}
+
+	wg.Wait()
+	return jobs
+}
+
+func (e *DetailEnricher) enrich(ctx context.Context, job *domain.Job) error {
+	link, err := url.Parse(job.Link)
+	if err != nil {
+		return fmt.Errorf("invalid job link: %w", err)
+	}
+
+	if err := e.limiter.wait(ctx, link.Host); err != nil {
+		return err
+	}
+
+	doc, finalURL, err := e.fetch(ctx, job.Link)
+	if err != nil {
+		return err
+	}
+
+	e.extract(job, doc, finalURL)
+	if postings := parser.JobPostings(doc); len(postings) > 0 {
+		fillFromPosting(job, postings[0])
+	}
+	e.logger.Debug("Enriched job", zap.String("external_id", job.ExternalID))
+	return nil
+}
+
+func (e *DetailEnricher) fetch(ctx context.Context, link string) (*goquery.Document, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", e.userAgent)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, nil, fmt.Errorf("unexpected status %d for %s", resp.StatusCode, link)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse detail page: %w", err)
+	}
+
+	return doc, resp.Request.URL, nil
+}
+
+func (e *DetailEnricher) extract(job *domain.Job, doc *goquery.Document, finalURL *url.URL) {
+	sel := e.selectors
+
+	description := doc.Find(sel.Description).First()
+	job.FullDescription = cleanText(description.Text())
+
+	if sel.Requirements != "" {
+		job.Requirements = cleanText(doc.Find(sel.Requirements).First().Text())
+	}
+	if job.Requirements == "" {
+		job.Requirements = requirementsFromHeading(description)
+	}
+
+	job.EmployerInfo = cleanText(doc.Find(sel.Employer).First().Text())
+
+	if href, ok := doc.Find(sel.ApplyLink).First().Attr("href"); ok {
+		if ref, err := url.Parse(strings.TrimSpace(href)); err == nil {
+			job.OriginalURL = finalURL.ResolveReference(ref).String()
+		}
+	}
+	if job.OriginalURL == "" && !sameSite(finalURL, job.Link) {
+		// the job link redirected straight to the employer
+		job.OriginalURL = finalURL.String()
+	}
+}
+
+// fillFromPosting fills what neither the card nor the selectors of the detail
+// page gave from the JobPosting the page embeds.
+func fillFromPosting(job *domain.Job, posting domain.Job) {
+	if job.FullDescription == "" {
+		job.FullDescription = posting.Description
+	}
+	if job.Company == "" {
+		job.Company = posting.Company
+	}
+	if job.City == "" {
+		job.City = posting.City
+	}
+	// what the card states is normalized later and kept over the posting
+	if job.Salary == "" {
+		job.Salary = posting.Salary
+		job.Pay = posting.Pay
+	}
+	if job.Date == "" {
+		job.Date = posting.Date
+		job.PostedAt = posting.PostedAt
+	}
+	if job.Employment == "" {
+		job.Employment = posting.Employment
+	}
+	if job.WorkMode == "" {
+		job.WorkMode = posting.WorkMode
+	}
+}
+
+// requirementsFromHeading finds a "Requirements" heading inside the
+// description and returns the list that follows it.
+func requirementsFromHeading(description *goquery.Selection) string {
+	var requirements string
+
+	description.Find("h2, h3, h4, p, strong, b").EachWithBreak(func(_ int, heading *goquery.Selection) bool {
+		if !requirementsHeading.MatchString(cleanText(heading.Text())) {
+			return true
+		}
+
+		list := heading.NextAllFiltered("ul, ol").First()
+		if list.Length() == 0 {
+			list = heading.Parent().NextAllFiltered("ul, ol").First()
+		}
+
+		var items []string
+		list.Find("li").Each(func(_ int, item *goquery.Selection) {
+			if text := cleanText(item.Text()); text != "" {
+				items = append(items, "• "+text)
+			}
+		})
+		requirements = strings.Join(items, "\n")
+		return requirements == ""
+	})
+
+	return requirements
+}
+
+func sameSite(finalURL *url.URL, link string) bool {
+	original, err := url.Parse(link)
+	if err != nil {
+		return true
+	}
+	return strings.EqualFold(original.Hostname(), finalURL.Hostname())
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
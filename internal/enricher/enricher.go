@@ -0,0 +1,20 @@
+package enricher
+
+import (
+	"context"
+	"jooble-parser/internal/domain"
+)
+
+type Enricher interface {
+	Enrich(ctx context.Context, jobs []domain.Job) []domain.Job
+}
+
+type NopEnricher struct{}
+
+func NewNopEnricher() Enricher {
+	return &NopEnricher{}
+}
+
+func (e *NopEnricher) Enrich(ctx context.Context, jobs []domain.Job) []domain.Job {
+	return jobs
+}
@@ -0,0 +1,155 @@
+package filter
+
+import (
+	"cmp"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/normalize"
+	"slices"
+	"time"
+)
+
+// Filter selects and orders the new jobs that are signalled.
+type Filter interface {
+	Apply(jobs []domain.Job) []domain.Job
+}
+
+type rule func(job domain.Job) bool
+
+// JobFilter keeps the jobs every rule accepts.
+type JobFilter struct {
+	rules    []rule
+	sortBy   string
+	currency string // of the salaries that are compared
+}
+
+func NewJobFilter(cfg *config.FilterConfig) Filter {
+	filter := &JobFilter{sortBy: cfg.SortBy, currency: cfg.Currency}
+
+	if len(cfg.Employment) > 0 {
+		filter.rules = append(filter.rules, oneOf(cfg.Employment, func(job domain.Job) string {
+			return string(job.Employment)
+		}))
+	}
+	if len(cfg.WorkModes) > 0 {
+		filter.rules = append(filter.rules, oneOf(cfg.WorkModes, func(job domain.Job) string {
+			return string(job.WorkMode)
+		}))
+	}
+	if len(cfg.Cities) > 0 {
+		filter.rules = append(filter.rules, located(canonical(cfg.Cities, normalize.CanonicalCity), func(job domain.Job) string {
+			return job.Location.City
+		}))
+	}
+	if len(cfg.Countries) > 0 {
+		filter.rules = append(filter.rules, located(canonical(cfg.Countries, normalize.CanonicalCountry), func(job domain.Job) string {
+			return job.Location.Country
+		}))
+	}
+	if cfg.MaxAge > 0 {
+		filter.rules = append(filter.rules, maxAge(cfg.GetMaxAgeDuration()))
+	}
+	if cfg.MinSalary > 0 {
+		filter.rules = append(filter.rules, minSalary(cfg.MinSalary, cfg.Currency, cfg.RequireSalary))
+	} else if cfg.RequireSalary {
+		filter.rules = append(filter.rules, func(job domain.Job) bool {
+			return job.Pay.Known()
+		})
+	}
+
+	return filter
+}
+
+func (f *JobFilter) Apply(jobs []domain.Job) []domain.Job {
+	kept := make([]domain.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if f.accepts(job) {
+			kept = append(kept, job)
+		}
+	}
+
+	switch f.sortBy {
+	case config.SortBySalary:
+		// the best paid first, jobs without a salary or paid in another
+		// currency last
+		slices.SortStableFunc(kept, func(a, b domain.Job) int {
+			return cmp.Compare(f.monthly(b), f.monthly(a))
+		})
+	case config.SortByDate:
+		// the newest first, jobs without a date last
+		slices.SortStableFunc(kept, func(a, b domain.Job) int {
+			return b.PostedAt.Compare(a.PostedAt)
+		})
+	}
+
+	return kept
+}
+
+// monthly returns the monthly pay of job if it is in the currency of the
+// filter and -1 if it can't be compared.
+func (f *JobFilter) monthly(job domain.Job) int64 {
+	if !job.Pay.Known() || job.Pay.Currency != f.currency {
+		return -1
+	}
+	return job.Pay.Monthly()
+}
+
+func (f *JobFilter) accepts(job domain.Job) bool {
+	for _, rule := range f.rules {
+		if !rule(job) {
+			return false
+		}
+	}
+	return true
+}
+
+// minSalary accepts the jobs that pay at least min per month. A salary that
+// is unknown or in another currency can't be compared and is accepted unless
+// a salary is required.
+func minSalary(min int64, currency string, required bool) rule {
+	return func(job domain.Job) bool {
+		if !job.Pay.Known() || job.Pay.Currency != currency {
+			return !required
+		}
+		return job.Pay.Monthly() >= min
+	}
+}
+
+// maxAge accepts the jobs posted within age. A job without a posting date is
+// accepted.
+func maxAge(age time.Duration) rule {
+	return func(job domain.Job) bool {
+		return job.PostedAt.IsZero() || time.Since(job.PostedAt) <= age
+	}
+}
+
+// oneOf accepts the jobs whose value is one of values. A job where the value
+// is unknown is accepted.
+func oneOf(values []string, value func(job domain.Job) string) rule {
+	return func(job domain.Job) bool {
+		v := value(job)
+		return v == "" || slices.Contains(values, v)
+	}
+}
+
+// located accepts the jobs placed in one of places and the remote ones, which
+// can be done from anywhere. A job where the place is unknown is accepted.
+func located(places []string, place func(job domain.Job) string) rule {
+	inPlace := oneOf(places, place)
+	return func(job domain.Job) bool {
+		return job.Location.Remote || inPlace(job)
+	}
+}
+
+// canonical resolves names the way the normalizer stores them, names the
+// gazetteer doesn't know are kept as they are.
+func canonical(names []string, resolve func(name string) (string, bool)) []string {
+	resolved := make([]string, 0, len(names))
+	for _, name := range names {
+		if canonical, ok := resolve(name); ok {
+			name = canonical
+		}
+		resolved = append(resolved, name)
+	}
+	return resolved
+}
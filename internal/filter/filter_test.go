@@ -0,0 +1,60 @@
+package filter
+
+import (
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/domain"
+	"slices"
+	"testing"
+)
+
+func paid(id string, min int64, currency string) domain.Job {
+	job := domain.Job{ExternalID: id}
+	if currency != "" {
+		job.Pay = domain.Salary{Min: min, Max: min, Currency: currency}
+	}
+	return job
+}
+
+func ids(jobs []domain.Job) []string {
+	result := make([]string, len(jobs))
+	for i, job := range jobs {
+		result[i] = job.ExternalID
+	}
+	return result
+}
+
+var jobs = []domain.Job{
+	paid("usd", 3000, domain.CurrencyUSD),
+	paid("low", 40000, domain.CurrencyUAH),
+	paid("unknown", 0, ""),
+	paid("high", 90000, domain.CurrencyUAH),
+	paid("eur", 5000, domain.CurrencyEUR),
+}
+
+func TestSortBySalary(t *testing.T) {
+	f := NewJobFilter(&config.FilterConfig{SortBy: config.SortBySalary, Currency: domain.CurrencyUAH})
+
+	// salaries in other currencies are not compared and keep their order
+	// after the ones in UAH with the unknown ones
+	got := ids(f.Apply(slices.Clone(jobs)))
+	if want := []string{"high", "low", "usd", "unknown", "eur"}; !slices.Equal(got, want) {
+		t.Errorf("Apply() = %v, want %v", got, want)
+	}
+}
+
+func TestMinSalary(t *testing.T) {
+	tests := []struct {
+		required bool
+		want     []string
+	}{
+		{false, []string{"usd", "unknown", "high", "eur"}},
+		{true, []string{"high"}},
+	}
+
+	for _, tt := range tests {
+		f := NewJobFilter(&config.FilterConfig{MinSalary: 50000, Currency: domain.CurrencyUAH, RequireSalary: tt.required})
+		if got := ids(f.Apply(slices.Clone(jobs))); !slices.Equal(got, tt.want) {
+			t.Errorf("Apply() requiring a salary %v = %v, want %v", tt.required, got, tt.want)
+		}
+	}
+}
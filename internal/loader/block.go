@@ -0,0 +1,57 @@
+package loader
+
+import (
+	"context"
+	"jooble-parser/internal/config"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// requestBlocker fails the requests of a tab that match its patterns, so
+// images, fonts and trackers are never downloaded.
+type requestBlocker struct {
+	patterns []*fetch.RequestPattern
+}
+
+func newRequestBlocker(cfg *config.ChromeBlock) *requestBlocker {
+	var patterns []*fetch.RequestPattern
+	for _, resourceType := range cfg.ResourceTypes {
+		patterns = append(patterns, &fetch.RequestPattern{
+			URLPattern:   "*",
+			ResourceType: network.ResourceType(resourceType),
+			RequestStage: fetch.RequestStageRequest,
+		})
+	}
+	for _, pattern := range cfg.UrlPatterns {
+		patterns = append(patterns, &fetch.RequestPattern{
+			URLPattern:   pattern,
+			RequestStage: fetch.RequestStageRequest,
+		})
+	}
+
+	return &requestBlocker{patterns: patterns}
+}
+
+// attach intercepts the blocked requests of the tab of tabCtx. Only matching
+// requests are paused, so every paused request is failed.
+func (b *requestBlocker) attach(tabCtx context.Context) chromedp.Action {
+	if len(b.patterns) == 0 {
+		return chromedp.ActionFunc(func(context.Context) error { return nil })
+	}
+
+	chromedp.ListenTarget(tabCtx, func(ev any) {
+		paused, ok := ev.(*fetch.EventRequestPaused)
+		if !ok {
+			return
+		}
+
+		// the listener must not block, commands are sent from a goroutine
+		go func() {
+			_ = chromedp.Run(tabCtx, fetch.FailRequest(paused.RequestID, network.ErrorReasonBlockedByClient))
+		}()
+	})
+
+	return fetch.Enable().WithPatterns(b.patterns)
+}
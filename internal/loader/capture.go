@@ -0,0 +1,68 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// ResponseCapturer loads a page like an HtmlLoader and returns the bodies of
+// the XHR and fetch responses the page received whose url matches.
+type ResponseCapturer interface {
+	HtmlLoader
+	Capture(url string, ctx context.Context, match func(responseURL string) bool) ([][]byte, error)
+}
+
+type responseCapture struct {
+	mu       sync.Mutex
+	match    func(responseURL string) bool
+	matched  map[network.RequestID]struct{}
+	finished []network.RequestID
+	bodies   [][]byte
+}
+
+func newResponseCapture(match func(responseURL string) bool) *responseCapture {
+	return &responseCapture{
+		match:   match,
+		matched: make(map[network.RequestID]struct{}),
+	}
+}
+
+func (c *responseCapture) listen(ev any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventResponseReceived:
+		if ev.Type != network.ResourceTypeXHR && ev.Type != network.ResourceTypeFetch {
+			return
+		}
+		if c.match(ev.Response.URL) {
+			c.matched[ev.RequestID] = struct{}{}
+		}
+	case *network.EventLoadingFinished:
+		if _, ok := c.matched[ev.RequestID]; ok {
+			c.finished = append(c.finished, ev.RequestID)
+		}
+	}
+}
+
+// collect reads the bodies of every matched response that finished loading.
+// It has to run while the tab is still open.
+func (c *responseCapture) collect(ctx context.Context) error {
+	c.mu.Lock()
+	finished := append([]network.RequestID(nil), c.finished...)
+	c.mu.Unlock()
+
+	for _, id := range finished {
+		body, err := network.GetResponseBody(id).Do(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		c.bodies = append(c.bodies, body)
+	}
+
+	return nil
+}
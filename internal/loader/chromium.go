@@ -2,73 +2,470 @@ package loader
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"jooble-parser/internal/artifacts"
 	"jooble-parser/internal/config"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/target"
 	"github.com/chromedp/chromedp"
 	"go.uber.org/zap"
 )
 
 type (
 	ChromeLoader struct {
-		options        []chromedp.ExecAllocatorOption
+		session        *browserSession
 		userDataFolder string
+		policy         string
+		rotateEvery    int
+		loads          int    // loads since the profile was last wiped
+		profileDir     string // user data dir of the running browser
+		supervisor     *supervisor
+		timeout        time.Duration
+		waits          []config.ChromeWait
+		paging         config.PagingConfig
+		rotation       *rotation
+		blocker        *requestBlocker
+		artifacts      artifacts.Store
 		logger         *zap.Logger
 	}
 )
 
-func NewChromeLoader(cfg *config.ChromeConfig, logger *zap.Logger) HtmlLoader {
-	return &ChromeLoader{
-		options: []chromedp.ExecAllocatorOption{
-			chromedp.ExecPath(cfg.ExePath),
-			chromedp.UserDataDir(cfg.UserDataFolder),
-			chromedp.Flag("headless", false),
-			chromedp.NoFirstRun,
-			chromedp.NoDefaultBrowserCheck,
-		},
-		logger:         logger,
-		userDataFolder: cfg.UserDataFolder,
+const (
+	pageGrowthTimeout = 10 * time.Second
+	pageGrowthPoll    = 500 * time.Millisecond
+	diagnoseTimeout   = 5 * time.Second
+
+	temporaryProfilePattern = "load-*"
+)
+
+func NewChromeLoader(cfg *config.Config, store artifacts.Store, logger *zap.Logger) HtmlLoader {
+	return newChromeLoader(cfg, store, logger)
+}
+
+func NewChromeCapturer(cfg *config.Config, store artifacts.Store, logger *zap.Logger) ResponseCapturer {
+	return newChromeLoader(cfg, store, logger)
+}
+
+func newChromeLoader(cfg *config.Config, store artifacts.Store, logger *zap.Logger) *ChromeLoader {
+	loader := &ChromeLoader{
+		logger:      logger,
+		policy:      cfg.Chrome.Session.Policy,
+		rotateEvery: cfg.Chrome.Session.RotateEvery,
+		timeout:     cfg.Chrome.GetTimeoutDuration(),
+		waits:       cfg.Chrome.Waits,
+		paging:      cfg.Loader.Paging,
+		rotation:    newRotation(&cfg.Rotation, logger),
+		blocker:     newRequestBlocker(&cfg.Chrome.Block),
+		artifacts:   store,
+	}
+
+	if cfg.Chrome.RemoteUrl != "" {
+		// the profile of a remote browser is not ours to manage
+		loader.session = newBrowserSession(remoteAllocator(cfg.Chrome.RemoteUrl), nil, logger)
+		return loader
+	}
+
+	options := []chromedp.ExecAllocatorOption{
+		chromedp.ExecPath(cfg.Chrome.ExePath),
+		chromedp.Flag("headless", cfg.Chrome.Headless),
+		chromedp.NoFirstRun,
+		chromedp.NoDefaultBrowserCheck,
+		chromedp.ModifyCmdFunc(browserCmd),
+	}
+	for name, value := range cfg.Chrome.Flags {
+		options = append(options, flagOption(name, value))
+	}
+
+	supervisor := newSupervisor(cfg.Chrome.UserDataFolder, logger)
+	if err := supervisor.killStale(); err != nil {
+		logger.Warn("Failed to look for stale browsers", zap.Error(err))
+	}
+	if err := removeStaleProfiles(cfg.Chrome.UserDataFolder); err != nil {
+		logger.Warn("Failed to remove stale temporary profiles", zap.Error(err))
+	}
+
+	loader.userDataFolder = cfg.Chrome.UserDataFolder
+	loader.profileDir = cfg.Chrome.UserDataFolder
+	loader.supervisor = supervisor
+	loader.session = newBrowserSession(execAllocator(options, func() string {
+		return loader.profileDir
+	}), supervisor, logger)
+
+	return loader
+}
+
+func flagOption(name string, value string) chromedp.ExecAllocatorOption {
+	switch strings.ToLower(value) {
+	case "true":
+		return chromedp.Flag(name, true)
+	case "false":
+		return chromedp.Flag(name, false)
+	default:
+		return chromedp.Flag(name, value)
 	}
 }
 
 func (loader *ChromeLoader) Load(url string, ctx context.Context) (string, error) {
-	ctx, cancel := chromedp.NewExecAllocator(context.Background(), loader.options...)
-	defer cancel()
+	id := loader.rotation.pick()
+
+	var html string
+	err := loader.withProfile(func() error {
+		return loader.retryOnCrash(url, ctx, func() (err error) {
+			html, err = loader.load(url, ctx, id, nil)
+			return err
+		})
+	})
+	loader.rotation.report(id, err)
+
+	return html, err
+}
+
+func (loader *ChromeLoader) Capture(url string, ctx context.Context, match func(responseURL string) bool) ([][]byte, error) {
+	id := loader.rotation.pick()
+
+	var bodies [][]byte
+	err := loader.withProfile(func() error {
+		return loader.retryOnCrash(url, ctx, func() error {
+			capture := newResponseCapture(match)
+			if _, err := loader.load(url, ctx, id, capture); err != nil {
+				return err
+			}
+			bodies = capture.bodies
+			return nil
+		})
+	})
+	loader.rotation.report(id, err)
+
+	return bodies, err
+}
+
+func (loader *ChromeLoader) retryOnCrash(url string, ctx context.Context, run func() error) error {
+	err := run()
+	if err != nil && ctx.Err() == nil && loader.session.crashed() {
+		loader.logger.Warn("Browser crashed during load, retrying", zap.String("url", url), zap.Error(err))
+		err = run()
+	}
 
-	ctx, cancel = chromedp.NewContext(ctx)
+	return err
+}
+
+// load opens url in a new tab and returns its html. A non-nil capture watches
+// the network traffic of the tab and collects the matched responses before
+// the tab is closed.
+func (loader *ChromeLoader) load(url string, parent context.Context, id identity, capture *responseCapture) (string, error) {
+	var opts []chromedp.ContextOption
+	if id.proxy != nil {
+		// a proxy can only be set per browser context, not per tab
+		proxy := id.proxy.Scheme + "://" + id.proxy.Host
+		opts = append(opts, chromedp.WithNewBrowserContext(func(p *target.CreateBrowserContextParams) *target.CreateBrowserContextParams {
+			return p.WithProxyServer(proxy)
+		}))
+	}
+
+	layout := layoutOf(parent, loader.waits, &loader.paging)
+
+	tabCtx, cancel, err := loader.session.tab(parent, opts...)
+	if err != nil {
+		return "", err
+	}
 	defer cancel()
 
-	ctx, cancel = context.WithTimeout(ctx, 90*time.Second)
+	if capture != nil {
+		chromedp.ListenTarget(tabCtx, capture.listen)
+	}
+
+	ctx, cancel := context.WithTimeout(tabCtx, loader.timeout)
 	defer cancel()
-	var html string
+
+	if err := chromedp.Run(ctx, loader.blocker.attach(tabCtx)); err != nil {
+		return "", fmt.Errorf("chromedp error: %w", err)
+	}
+
+	if id.profile != nil {
+		if err := chromedp.Run(ctx, applyProfile(id.profile)); err != nil {
+			return "", fmt.Errorf("chromedp error: %w", err)
+		}
+	}
 
 	loader.logger.Debug("Loading", zap.String("url", url))
-	err := chromedp.Run(ctx,
-		chromedp.Navigate(url),
-		chromedp.WaitVisible(`body`, chromedp.ByQuery),
-		chromedp.Sleep(3*time.Second),
+	resp, err := chromedp.RunResponse(ctx, chromedp.Navigate(url))
+	if err != nil {
+		return "", loader.keepArtifacts(parent, tabCtx, "", loader.failure(tabCtx, url, 0, layout, err))
+	}
+	status := int(resp.Status)
+
+	var html string
+	actions := []chromedp.Action{}
+	for _, wait := range layout.Waits {
+		actions = append(actions,
+			chromedp.WaitVisible(wait.Selector, chromedp.ByQuery),
+			chromedp.Sleep(wait.GetDelayDuration()),
+		)
+	}
+	actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+		return loader.expand(ctx, layout)
+	}))
+	if capture != nil {
+		actions = append(actions, chromedp.ActionFunc(capture.collect))
+	}
+	actions = append(actions, chromedp.OuterHTML("html", &html))
+
+	if err := chromedp.Run(ctx, actions...); err != nil {
+		return "", loader.keepArtifacts(parent, tabCtx, "", loader.failure(tabCtx, url, status, layout, err))
+	}
+
+	// a captured page is judged by its responses, not by the markup of its cards
+	if capture != nil {
+		if len(capture.bodies) == 0 {
+			if err := detectBlock(status, html); err != nil {
+				return "", loader.keepArtifacts(parent, tabCtx, html, fmt.Errorf("%w: %s", err, url))
+			}
+		}
+		return html, nil
+	}
+
+	if err := classify(status, html, layout); err != nil {
+		return "", loader.keepArtifacts(parent, tabCtx, html, fmt.Errorf("%w: %s", err, url))
+	}
+
+	return html, nil
+}
 
-		chromedp.WaitVisible(`div[data-test-name="_jobCard"]`, chromedp.ByQuery),
-		chromedp.Sleep(2*time.Second),
+// keepArtifacts saves a full page screenshot and the html of a failed load
+// and annotates err with their location. The run ID is taken from parent.
+// An empty search is a valid result and keeps nothing.
+func (loader *ChromeLoader) keepArtifacts(parent context.Context, tabCtx context.Context, html string, err error) error {
+	if errors.Is(err, ErrEmptyResults) || tabCtx.Err() != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(tabCtx, diagnoseTimeout)
+	defer cancel()
+
+	var dir string
+	var screenshot []byte
+	if shotErr := chromedp.Run(ctx, chromedp.FullScreenshot(&screenshot, 90)); shotErr != nil {
+		loader.logger.Debug("Failed to take screenshot", zap.Error(shotErr))
+	} else if path, saveErr := loader.artifacts.Save(parent, "screenshot.jpg", screenshot); saveErr != nil {
+		loader.logger.Warn("Failed to save screenshot", zap.Error(saveErr))
+	} else if path != "" {
+		dir = filepath.Dir(path)
+	}
+
+	if html == "" {
+		if htmlErr := chromedp.Run(ctx, chromedp.OuterHTML("html", &html)); htmlErr != nil {
+			loader.logger.Debug("Failed to read page html", zap.Error(htmlErr))
+		}
+	}
+	if html != "" {
+		if path, saveErr := loader.artifacts.Save(parent, "page.html", []byte(html)); saveErr != nil {
+			loader.logger.Warn("Failed to save page html", zap.Error(saveErr))
+		} else if path != "" {
+			dir = filepath.Dir(path)
+		}
+	}
+
+	return artifacts.Wrap(err, dir)
+}
+
+func applyProfile(profile *config.BrowserProfile) chromedp.Action {
+	actions := chromedp.Tasks{
+		emulation.SetUserAgentOverride(profile.UserAgent),
+	}
+	if profile.Width > 0 && profile.Height > 0 {
+		actions = append(actions, chromedp.EmulateViewport(int64(profile.Width), int64(profile.Height)))
+	}
+	return actions
+}
+
+// failure turns a chromedp error into a typed loader error. A wait that ran
+// out of time is diagnosed by the page it stopped on: a captcha, a block page
+// or a fully loaded page without cards are reported as such.
+func (loader *ChromeLoader) failure(tabCtx context.Context, url string, status int, layout Layout, err error) error {
+	if !errors.Is(err, context.DeadlineExceeded) || tabCtx.Err() != nil {
+		return fmt.Errorf("chromedp error: %w", err)
+	}
 
+	ctx, cancel := context.WithTimeout(tabCtx, diagnoseTimeout)
+	defer cancel()
+
+	var html, state string
+	if diagErr := chromedp.Run(ctx,
+		chromedp.Evaluate(`document.readyState`, &state),
 		chromedp.OuterHTML("html", &html),
-	)
+	); diagErr != nil {
+		return fmt.Errorf("%w: %s: %v", ErrTimeout, url, err)
+	}
+
+	cause := classify(status, html, layout)
+	if cause == nil || (errors.Is(cause, ErrLayoutChanged) && state != "complete") {
+		return fmt.Errorf("%w: %s: %v", ErrTimeout, url, err)
+	}
+
+	return fmt.Errorf("%w: %s", cause, url)
+}
+
+// expand clicks "show more" or scrolls to the bottom of the result list until
+// the paging limits are reached or no more cards appear.
+func (loader *ChromeLoader) expand(ctx context.Context, layout Layout) error {
+	countJS := fmt.Sprintf(`document.querySelectorAll(%q).length`, layout.Cards)
+	moreJS := fmt.Sprintf(`(() => {
+		const more = document.querySelector(%q);
+		if (more) {
+			more.click();
+			return true;
+		}
+		window.scrollTo(0, document.body.scrollHeight);
+		return false;
+	})()`, layout.MoreSelector)
 
+	var cards int
+	if err := chromedp.Evaluate(countJS, &cards).Do(ctx); err != nil {
+		return err
+	}
+
+	for page := 2; page <= loader.paging.MaxPages; page++ {
+		if loader.paging.MaxCards > 0 && cards >= loader.paging.MaxCards {
+			break
+		}
+
+		var clicked bool
+		if err := chromedp.Evaluate(moreJS, &clicked).Do(ctx); err != nil {
+			return err
+		}
+
+		grown, err := loader.waitForMoreCards(ctx, countJS, cards)
+		if err != nil {
+			return err
+		}
+		if grown == cards {
+			break
+		}
+		cards = grown
+	}
+
+	loader.logger.Debug("Collected cards", zap.Int("cards", cards))
+	return nil
+}
+
+func (loader *ChromeLoader) waitForMoreCards(ctx context.Context, countJS string, cards int) (int, error) {
+	deadline := time.Now().Add(pageGrowthTimeout)
+
+	for time.Now().Before(deadline) {
+		if err := chromedp.Sleep(pageGrowthPoll).Do(ctx); err != nil {
+			return cards, err
+		}
+
+		var count int
+		if err := chromedp.Evaluate(countJS, &count).Do(ctx); err != nil {
+			return cards, err
+		}
+		if count > cards {
+			return count, nil
+		}
+	}
+
+	return cards, nil
+}
+
+// withProfile runs a load according to the session policy: an ephemeral
+// browser gets a temporary profile that is removed right after the load, a
+// rotated one is stopped and wiped every rotateEvery loads and a persistent
+// one keeps its profile. A failed cleanup is logged and does not fail the load
+// it follows.
+func (loader *ChromeLoader) withProfile(load func() error) error {
+	if loader.policy == config.SessionEphemeral && loader.userDataFolder != "" {
+		if err := os.MkdirAll(loader.userDataFolder, 0755); err != nil {
+			return fmt.Errorf("failed to create user data folder %s: %w", loader.userDataFolder, err)
+		}
+		dir, err := os.MkdirTemp(loader.userDataFolder, temporaryProfilePattern)
+		if err != nil {
+			return fmt.Errorf("failed to create temporary profile: %w", err)
+		}
+		loader.profileDir = dir
+	}
+
+	err := load()
+	if loader.supervisor != nil {
+		loader.supervisor.logStats()
+	}
+
+	var cleanupErr error
+	switch loader.policy {
+	case config.SessionEphemeral:
+		cleanupErr = loader.endSession()
+	case config.SessionRotate:
+		loader.loads++
+		if loader.loads >= loader.rotateEvery {
+			loader.logger.Info("Rotating browser profile", zap.Int("loads", loader.loads))
+			loader.loads = 0
+			cleanupErr = loader.endSession()
+		}
+	}
+	if cleanupErr != nil {
+		loader.logger.Error("Failed to clean up browser session", zap.Error(cleanupErr))
+	}
+
+	return err
+}
+
+// endSession stops the browser and removes the profile it used.
+func (loader *ChromeLoader) endSession() error {
+	closeErr := loader.session.Close()
+	return errors.Join(closeErr, loader.clearProfile())
+}
+
+func (loader *ChromeLoader) Close() error {
+	if loader.policy == config.SessionPersistent {
+		return loader.session.Close()
+	}
+	return loader.endSession()
+}
+
+// removeStaleProfiles removes the temporary profiles of ephemeral sessions
+// that were not cleaned up, e.g. because the application was killed.
+func removeStaleProfiles(userDataFolder string) error {
+	dirs, err := filepath.Glob(filepath.Join(userDataFolder, temporaryProfilePattern))
 	if err != nil {
-		return "", fmt.Errorf("chromedp error: %w", err)
+		return err
 	}
-	_ = chromedp.Cancel(ctx)
-	err = loader.clearUserData()
-	return html, nil
+
+	var errs []error
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove temporary profile %s: %w", dir, err))
+		}
+	}
+	return errors.Join(errs...)
 }
 
-func (loader *ChromeLoader) clearUserData() error {
-	if err := os.RemoveAll(loader.userDataFolder); err != nil {
-		return fmt.Errorf("error user data clearing %s: %w", loader.userDataFolder, err)
+// clearProfile removes the profile of the last browser. A temporary profile
+// is removed with its directory, a shared one only loses its contents.
+func (loader *ChromeLoader) clearProfile() error {
+	dir := loader.profileDir
+	if dir == "" {
+		return nil
+	}
+
+	if dir != loader.userDataFolder {
+		loader.profileDir = loader.userDataFolder
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("failed to remove temporary profile %s: %w", dir, err)
+		}
+		loader.logger.Debug("Removed temporary profile", zap.String("path", dir))
+		return nil
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear user data folder %s: %w", dir, err)
 	}
 
-	loader.logger.Debug("Clear user data folder", zap.String("path", loader.userDataFolder))
+	loader.logger.Debug("Clear user data folder", zap.String("path", dir))
 	return nil
 }
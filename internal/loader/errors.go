@@ -0,0 +1,81 @@
+package loader
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrTimeout       = errors.New("page load timed out")
+	ErrBlocked       = errors.New("access blocked by site")
+	ErrCaptcha       = errors.New("captcha challenge")
+	ErrEmptyResults  = errors.New("search returned no results")
+	ErrLayoutChanged = errors.New("job cards not found, page layout changed")
+)
+
+var (
+	captchaMarkers = []string{
+		"g-recaptcha",
+		"h-captcha",
+		"cf-turnstile",
+		"challenge-form",
+		"captcha-delivery",
+		"/cdn-cgi/challenge-platform",
+	}
+	blockedMarkers = []string{
+		"access denied",
+		"you have been blocked",
+		"attention required! | cloudflare",
+		"too many requests",
+		"request blocked",
+	}
+	emptyMarkers = []string{
+		"нічого не знайдено",
+		"ничего не найдено",
+		"no jobs found",
+		"nothing found",
+		"no results found",
+	}
+)
+
+// classify explains why a loaded page has no job cards of the layout. It
+// returns nil for a page that has them or embeds postings the layout accepts.
+func classify(status int, html string, layout Layout) error {
+	blocked := status == http.StatusForbidden || status == http.StatusTooManyRequests
+	if !blocked && (countCards(html, layout.Cards) > 0 || (layout.Postings != nil && layout.Postings(html))) {
+		return nil
+	}
+
+	if err := detectBlock(status, html); err != nil {
+		return err
+	}
+	if containsAny(strings.ToLower(html), emptyMarkers) {
+		return ErrEmptyResults
+	}
+	return ErrLayoutChanged
+}
+
+// detectBlock reports captcha and block pages regardless of job cards.
+func detectBlock(status int, html string) error {
+	lower := strings.ToLower(html)
+
+	switch {
+	case containsAny(lower, captchaMarkers):
+		return ErrCaptcha
+	case status == http.StatusForbidden || status == http.StatusTooManyRequests:
+		return ErrBlocked
+	case containsAny(lower, blockedMarkers):
+		return ErrBlocked
+	}
+	return nil
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
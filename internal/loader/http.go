@@ -0,0 +1,208 @@
+package loader
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"jooble-parser/internal/artifacts"
+	"jooble-parser/internal/config"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	neturl "net/url"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type (
+	HttpLoader struct {
+		client    *http.Client
+		headers   map[string]string
+		paging    config.PagingConfig
+		rotation  *rotation
+		artifacts artifacts.Store
+		logger    *zap.Logger
+	}
+
+	proxyKey struct{}
+)
+
+func NewHttpLoader(cfg *config.Config, store artifacts.Store, logger *zap.Logger) HtmlLoader {
+	// cookiejar.New never fails without options
+	jar, _ := cookiejar.New(nil)
+
+	headers := map[string]string{
+		"User-Agent": cfg.Http.UserAgent,
+		"Accept":     "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+	}
+	for key, value := range cfg.Http.Headers {
+		headers[key] = value
+	}
+
+	return &HttpLoader{
+		client: &http.Client{
+			Timeout: cfg.Http.GetTimeoutDuration(),
+			Jar:     jar,
+			Transport: &http.Transport{
+				Proxy:              proxyFromContext,
+				DisableCompression: true,
+			},
+		},
+		headers:   headers,
+		paging:    cfg.Loader.Paging,
+		rotation:  newRotation(&cfg.Rotation, logger),
+		artifacts: store,
+		logger:    logger,
+	}
+}
+
+// proxyFromContext routes a request through the proxy picked for its load,
+// falling back to the proxy from the environment.
+func proxyFromContext(req *http.Request) (*neturl.URL, error) {
+	if proxy, ok := req.Context().Value(proxyKey{}).(*neturl.URL); ok {
+		return proxy, nil
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+func (loader *HttpLoader) Load(url string, ctx context.Context) (string, error) {
+	id := loader.rotation.pick()
+	if id.proxy != nil {
+		ctx = context.WithValue(ctx, proxyKey{}, id.proxy)
+	}
+
+	html, err := loader.load(url, ctx, id)
+	loader.rotation.report(id, err)
+
+	return html, err
+}
+
+func (loader *HttpLoader) load(url string, ctx context.Context, id identity) (string, error) {
+	layout := layoutOf(ctx, nil, &loader.paging)
+
+	first, err := loader.loadPage(url, ctx, id, layout)
+	if err != nil {
+		return "", err
+	}
+	if err := classify(http.StatusOK, first, layout); err != nil {
+		err = fmt.Errorf("%w: %s", err, url)
+		if errors.Is(err, ErrEmptyResults) {
+			return "", err
+		}
+		return "", loader.keepArtifacts(ctx, first, err)
+	}
+
+	pages := []string{first}
+	cards := countCards(first, layout.Cards)
+
+	for page := 2; !layout.SinglePage && page <= loader.paging.MaxPages; page++ {
+		if loader.paging.MaxCards > 0 && cards >= loader.paging.MaxCards {
+			break
+		}
+
+		next, err := pageURL(url, layout.PageParam, page)
+		if err != nil {
+			return "", err
+		}
+
+		html, err := loader.loadPage(next, ctx, id, layout)
+		if err != nil {
+			loader.logger.Warn("Failed to load next page", zap.String("url", next), zap.Error(err))
+			break
+		}
+
+		found := countCards(html, layout.Cards)
+		if found == 0 {
+			break
+		}
+
+		pages = append(pages, html)
+		cards += found
+	}
+
+	loader.logger.Debug("Loaded pages", zap.Int("pages", len(pages)), zap.Int("cards", cards))
+	return joinPages(pages), nil
+}
+
+func (loader *HttpLoader) loadPage(url string, ctx context.Context, id identity, layout Layout) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for key, value := range loader.headers {
+		req.Header.Set(key, value)
+	}
+	if id.profile != nil {
+		req.Header.Set("User-Agent", id.profile.UserAgent)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	loader.logger.Debug("Loading", zap.String("url", url))
+	resp, err := loader.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return "", fmt.Errorf("%w: %s: %v", ErrTimeout, url, err)
+		}
+		return "", fmt.Errorf("http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := decodeBody(resp)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		if cause := classify(resp.StatusCode, body, layout); errors.Is(cause, ErrBlocked) || errors.Is(cause, ErrCaptcha) {
+			return "", loader.keepArtifacts(ctx, body, fmt.Errorf("%w: %s", cause, url))
+		}
+		return "", fmt.Errorf("unexpected status %d for %s", resp.StatusCode, url)
+	}
+
+	return body, nil
+}
+
+// keepArtifacts saves the html of a failed load and annotates err with its
+// location.
+func (loader *HttpLoader) keepArtifacts(ctx context.Context, html string, err error) error {
+	path, saveErr := loader.artifacts.Save(ctx, "page.html", []byte(html))
+	if saveErr != nil {
+		loader.logger.Warn("Failed to save page html", zap.Error(saveErr))
+		return err
+	}
+	if path == "" {
+		return err
+	}
+	return artifacts.Wrap(err, filepath.Dir(path))
+}
+
+func decodeBody(resp *http.Response) (string, error) {
+	var reader io.Reader = resp.Body
+
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return "", fmt.Errorf("failed to open gzip body: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to read body: %w", err)
+	}
+
+	return string(data), nil
+}
+
+func (loader *HttpLoader) Close() error {
+	loader.client.CloseIdleConnections()
+	return nil
+}
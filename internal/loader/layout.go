@@ -0,0 +1,49 @@
+package loader
+
+import (
+	"context"
+	"jooble-parser/internal/config"
+)
+
+type (
+	// Layout describes the search page of a site to the loaders. Empty
+	// fields keep the configured behaviour, which is the one of jooble.
+	Layout struct {
+		Cards        string              // selector of a job card, pages without one are classified
+		Waits        []config.ChromeWait // awaited by the chrome loader before the page is read
+		PageParam    string              // query parameter of the next page for the http loader
+		SinglePage   bool                // the page can't be paged by a query parameter, the http loader loads only it
+		MoreSelector string              // "show more" button clicked by the chrome loader
+
+		// Postings reports whether a page without cards embeds jobs the
+		// parser reads instead, such a page is not classified.
+		Postings func(html string) bool
+	}
+
+	layoutKey struct{}
+)
+
+// WithLayout makes the loads of ctx expect the page layout.
+func WithLayout(ctx context.Context, layout Layout) context.Context {
+	return context.WithValue(ctx, layoutKey{}, layout)
+}
+
+// layoutOf returns the layout a load of ctx expects, filled in with the
+// configured waits and paging.
+func layoutOf(ctx context.Context, waits []config.ChromeWait, paging *config.PagingConfig) Layout {
+	layout, _ := ctx.Value(layoutKey{}).(Layout)
+
+	if layout.Cards == "" {
+		layout.Cards = jobCardSelector
+	}
+	if len(layout.Waits) == 0 {
+		layout.Waits = waits
+	}
+	if layout.PageParam == "" {
+		layout.PageParam = paging.PageParam
+	}
+	if layout.MoreSelector == "" {
+		layout.MoreSelector = paging.MoreSelector
+	}
+	return layout
+}
@@ -4,4 +4,5 @@ import "context"
 
 type HtmlLoader interface {
 	Load(url string, ctx context.Context) (string, error)
+	Close() error
 }
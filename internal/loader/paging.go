@@ -0,0 +1,42 @@
+package loader
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// jobCardSelector is the job card of jooble, expected unless the layout of
+// the load says otherwise.
+const jobCardSelector = `div[data-test-name="_jobCard"]`
+
+func pageURL(rawURL string, param string, page int) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid url %s: %w", rawURL, err)
+	}
+
+	query := u.Query()
+	query.Set(param, strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+func countCards(html string, cardSelector string) int {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return 0
+	}
+	return doc.Find(cardSelector).Length()
+}
+
+// joinPages combines several result pages into one document. The HTML parser
+// folds the <html> and <body> of every following page into the first one, so
+// the cards of all pages end up in a single body.
+func joinPages(pages []string) string {
+	return strings.Join(pages, "\n")
+}
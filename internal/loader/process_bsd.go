@@ -0,0 +1,16 @@
+//go:build unix && !linux
+
+package loader
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// browserCmd starts the browser in a process group of its own.
+func browserCmd(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = new(syscall.SysProcAttr)
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
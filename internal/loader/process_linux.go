@@ -0,0 +1,16 @@
+package loader
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// browserCmd starts the browser in a process group of its own and, as
+// chromedp does by default, kills it when the application dies.
+func browserCmd(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = new(syscall.SysProcAttr)
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.SysProcAttr.Pdeathsig = syscall.SIGKILL
+}
@@ -0,0 +1,27 @@
+//go:build !unix
+
+package loader
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+func browserCmd(cmd *exec.Cmd) {}
+
+// killGroup kills pid, the processes it spawned are not grouped with it. It
+// reports false when pid is not running anymore.
+func killGroup(pid int) (bool, error) {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false, nil
+	}
+	if err := p.Kill(); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package loader
+
+import (
+	"errors"
+	"syscall"
+)
+
+// killGroup kills the process group led by pid, or pid alone when it leads
+// none. It reports false when neither is running anymore.
+func killGroup(pid int) (bool, error) {
+	err := syscall.Kill(-pid, syscall.SIGKILL)
+	if errors.Is(err, syscall.ESRCH) {
+		err = syscall.Kill(pid, syscall.SIGKILL)
+	}
+	if errors.Is(err, syscall.ESRCH) {
+		return false, nil
+	}
+	return err == nil, err
+}
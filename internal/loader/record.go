@@ -0,0 +1,89 @@
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// RecordingLoader saves every page fetched by the wrapped loader so it
+	// can be served back later by ReplayLoader.
+	RecordingLoader struct {
+		inner  HtmlLoader
+		dir    string
+		logger *zap.Logger
+	}
+
+	snapshotMeta struct {
+		URL       string    `json:"url"`
+		Timestamp time.Time `json:"timestamp"`
+		Hash      string    `json:"hash"`
+		File      string    `json:"file"`
+	}
+)
+
+func NewRecordingLoader(inner HtmlLoader, dir string, logger *zap.Logger) HtmlLoader {
+	return &RecordingLoader{
+		inner:  inner,
+		dir:    dir,
+		logger: logger,
+	}
+}
+
+func (loader *RecordingLoader) Load(url string, ctx context.Context) (string, error) {
+	html, err := loader.inner.Load(url, ctx)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := loader.save(url, html)
+	if err != nil {
+		loader.logger.Warn("Failed to record snapshot", zap.String("url", url), zap.Error(err))
+		return html, nil
+	}
+
+	loader.logger.Debug("Recorded snapshot", zap.String("url", url), zap.String("path", path))
+	return html, nil
+}
+
+func (loader *RecordingLoader) Close() error {
+	return loader.inner.Close()
+}
+
+func (loader *RecordingLoader) save(url string, html string) (string, error) {
+	if err := os.MkdirAll(loader.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create snapshot dir %s: %w", loader.dir, err)
+	}
+
+	sum := sha256.Sum256([]byte(html))
+	meta := snapshotMeta{
+		URL:       url,
+		Timestamp: time.Now().UTC(),
+		Hash:      hex.EncodeToString(sum[:]),
+	}
+	base := fmt.Sprintf("%s-%s", meta.Timestamp.Format("20060102T150405.000000000"), meta.Hash[:12])
+	meta.File = base + ".html"
+
+	htmlPath := filepath.Join(loader.dir, meta.File)
+	if err := os.WriteFile(htmlPath, []byte(html), 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal snapshot meta: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(loader.dir, base+".json"), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot meta: %w", err)
+	}
+
+	return htmlPath, nil
+}
@@ -0,0 +1,95 @@
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// ReplayLoader serves snapshots written by RecordingLoader. Successive loads
+// of the same url return its snapshots in recording order and keep returning
+// the newest one once all of them were served.
+type ReplayLoader struct {
+	mu        sync.Mutex
+	dir       string
+	snapshots map[string][]snapshotMeta
+	served    map[string]int
+	logger    *zap.Logger
+}
+
+func NewReplayLoader(dir string, logger *zap.Logger) (HtmlLoader, error) {
+	metaFiles, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots in %s: %w", dir, err)
+	}
+
+	snapshots := make(map[string][]snapshotMeta)
+	for _, path := range metaFiles {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot meta %s: %w", path, err)
+		}
+
+		var meta snapshotMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot meta %s: %w", path, err)
+		}
+		snapshots[meta.URL] = append(snapshots[meta.URL], meta)
+	}
+
+	for _, list := range snapshots {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Timestamp.Before(list[j].Timestamp)
+		})
+	}
+
+	logger.Info("Loaded snapshots", zap.String("dir", dir), zap.Int("urls", len(snapshots)))
+	return &ReplayLoader{
+		dir:       dir,
+		snapshots: snapshots,
+		served:    make(map[string]int),
+		logger:    logger,
+	}, nil
+}
+
+func (loader *ReplayLoader) Load(url string, ctx context.Context) (string, error) {
+	loader.mu.Lock()
+	list := loader.snapshots[url]
+	if len(list) == 0 {
+		loader.mu.Unlock()
+		return "", fmt.Errorf("no snapshot recorded for %s", url)
+	}
+
+	i := loader.served[url]
+	if i >= len(list) {
+		i = len(list) - 1
+	}
+	loader.served[url] = i + 1
+	meta := list[i]
+	loader.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(loader.dir, meta.File))
+	if err != nil {
+		return "", fmt.Errorf("failed to read snapshot %s: %w", meta.File, err)
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != meta.Hash {
+		return "", fmt.Errorf("snapshot %s does not match its hash", meta.File)
+	}
+
+	loader.logger.Debug("Replaying snapshot", zap.String("url", url), zap.String("file", meta.File))
+	return string(data), nil
+}
+
+func (loader *ReplayLoader) Close() error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const searchUrl = "https://ua.jooble.org/SearchResult?ukw=golang"
+
+// pagesLoader serves its pages one after another.
+type pagesLoader struct {
+	pages []string
+}
+
+func (loader *pagesLoader) Load(url string, ctx context.Context) (string, error) {
+	page := loader.pages[0]
+	loader.pages = loader.pages[1:]
+	return page, nil
+}
+
+func (loader *pagesLoader) Close() error {
+	return nil
+}
+
+func record(t *testing.T, pages ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	recorder := NewRecordingLoader(&pagesLoader{pages: pages}, dir, zap.NewNop())
+	for _, want := range pages {
+		html, err := recorder.Load(searchUrl, context.Background())
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if html != want {
+			t.Fatalf("Load() = %q, want the page of the inner loader %q", html, want)
+		}
+	}
+	return dir
+}
+
+func newReplay(t *testing.T, dir string) HtmlLoader {
+	t.Helper()
+
+	replay, err := NewReplayLoader(dir, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewReplayLoader() error = %v", err)
+	}
+	return replay
+}
+
+func TestRecordReplay(t *testing.T) {
+	page := "<html><body><div>Go Developer</div></body></html>"
+	replay := newReplay(t, record(t, page))
+
+	html, err := replay.Load(searchUrl, context.Background())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if html != page {
+		t.Errorf("Load() = %q, want %q", html, page)
+	}
+}
+
+func TestReplayOrder(t *testing.T) {
+	replay := newReplay(t, record(t, "first", "second"))
+
+	// the newest snapshot is served again once all were served
+	for _, want := range []string{"first", "second", "second"} {
+		html, err := replay.Load(searchUrl, context.Background())
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		if html != want {
+			t.Errorf("Load() = %q, want %q", html, want)
+		}
+	}
+}
+
+func TestReplayHashMismatch(t *testing.T) {
+	dir := record(t, "recorded")
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("got snapshots %v, %v, want one", files, err)
+	}
+	if err := os.WriteFile(files[0], []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = newReplay(t, dir).Load(searchUrl, context.Background())
+	if err == nil || !strings.Contains(err.Error(), "does not match its hash") {
+		t.Errorf("Load() error = %v, want a hash mismatch", err)
+	}
+}
+
+func TestReplayUnknownUrl(t *testing.T) {
+	replay := newReplay(t, record(t, "recorded"))
+
+	_, err := replay.Load("https://ua.jooble.org/SearchResult?ukw=rust", context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no snapshot recorded") {
+		t.Errorf("Load() error = %v, want no snapshot", err)
+	}
+}
@@ -0,0 +1,140 @@
+package loader
+
+import (
+	"errors"
+	"jooble-parser/internal/config"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// rotation hands out a proxy and a browser profile for every load and
+	// keeps track of how the proxies behave. A proxy that fails too often or
+	// gets blocked is evicted for a while.
+	rotation struct {
+		mu          sync.Mutex
+		proxies     []*proxyState
+		profiles    []config.BrowserProfile
+		nextProxy   int
+		nextProfile int
+		maxFailures int
+		evictFor    time.Duration
+		logger      *zap.Logger
+	}
+
+	proxyState struct {
+		url          *url.URL
+		failures     int
+		evictedUntil time.Time
+	}
+
+	// identity is what a single load looks like to the site. Both fields are
+	// nil when rotation is not configured.
+	identity struct {
+		proxy   *url.URL
+		profile *config.BrowserProfile
+	}
+)
+
+func newRotation(cfg *config.RotationConfig, logger *zap.Logger) *rotation {
+	r := &rotation{
+		profiles:    cfg.Profiles,
+		maxFailures: cfg.MaxFailures,
+		evictFor:    cfg.GetEvictForDuration(),
+		logger:      logger,
+	}
+
+	for _, raw := range cfg.Proxies {
+		proxyURL, err := url.Parse(raw)
+		if err != nil {
+			logger.Warn("Skipping invalid proxy", zap.String("proxy", raw), zap.Error(err))
+			continue
+		}
+		r.proxies = append(r.proxies, &proxyState{url: proxyURL})
+	}
+
+	return r
+}
+
+func (r *rotation) pick() identity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var id identity
+	if proxy := r.pickProxy(time.Now()); proxy != nil {
+		id.proxy = proxy.url
+	}
+	if len(r.profiles) > 0 {
+		id.profile = &r.profiles[r.nextProfile%len(r.profiles)]
+		r.nextProfile++
+	}
+
+	return id
+}
+
+// pickProxy returns the next healthy proxy in turn. When every proxy is
+// evicted the one that comes back first is used rather than going direct.
+func (r *rotation) pickProxy(now time.Time) *proxyState {
+	if len(r.proxies) == 0 {
+		return nil
+	}
+
+	for range r.proxies {
+		proxy := r.proxies[r.nextProxy%len(r.proxies)]
+		r.nextProxy++
+		if !now.Before(proxy.evictedUntil) {
+			return proxy
+		}
+	}
+
+	soonest := r.proxies[0]
+	for _, proxy := range r.proxies[1:] {
+		if proxy.evictedUntil.Before(soonest.evictedUntil) {
+			soonest = proxy
+		}
+	}
+	return soonest
+}
+
+// report records the outcome of a load made through the proxy of id. Empty
+// results and layout changes say nothing about the proxy and count as success.
+func (r *rotation) report(id identity, err error) {
+	if id.proxy == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var proxy *proxyState
+	for _, state := range r.proxies {
+		if state.url == id.proxy {
+			proxy = state
+		}
+	}
+	if proxy == nil {
+		return
+	}
+
+	switch {
+	case err == nil, errors.Is(err, ErrEmptyResults), errors.Is(err, ErrLayoutChanged):
+		proxy.failures = 0
+		return
+	case errors.Is(err, ErrBlocked), errors.Is(err, ErrCaptcha):
+		proxy.failures = r.maxFailures
+	default:
+		proxy.failures++
+	}
+
+	if proxy.failures >= r.maxFailures {
+		proxy.failures = 0
+		proxy.evictedUntil = time.Now().Add(r.evictFor)
+		r.logger.Warn("Proxy evicted",
+			zap.String("proxy", proxy.url.Redacted()),
+			zap.Time("until", proxy.evictedUntil),
+			zap.Error(err))
+	}
+}
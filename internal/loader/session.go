@@ -0,0 +1,162 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+)
+
+const browserCloseTimeout = 10 * time.Second
+
+// allocator creates the context a browser is launched or attached from.
+type allocator func(ctx context.Context) (context.Context, context.CancelFunc)
+
+// browserSession keeps a single Chrome process alive between loads and opens
+// a fresh tab for every load. A browser that died is started again on the
+// next request for a tab. The browser is bound to the context of the load that
+// started it, so cancelling the application context stops it as well.
+//
+// A session attached to a remote browser only owns its tabs, stopping it
+// closes them and the connection but leaves the browser running.
+type browserSession struct {
+	mu            sync.Mutex
+	allocate      allocator
+	allocCancel   context.CancelFunc
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+	supervisor    *supervisor // nil for a remote browser
+	logger        *zap.Logger
+}
+
+func newBrowserSession(allocate allocator, supervisor *supervisor, logger *zap.Logger) *browserSession {
+	return &browserSession{
+		allocate:   allocate,
+		supervisor: supervisor,
+		logger:     logger,
+	}
+}
+
+// execAllocator launches a local browser with the user data dir returned by
+// profile at the time of the launch.
+func execAllocator(options []chromedp.ExecAllocatorOption, profile func() string) allocator {
+	return func(ctx context.Context) (context.Context, context.CancelFunc) {
+		opts := append(slices.Clip(options), chromedp.UserDataDir(profile()))
+		return chromedp.NewExecAllocator(ctx, opts...)
+	}
+}
+
+// remoteAllocator attaches to the browser behind a devtools websocket or
+// http endpoint.
+func remoteAllocator(url string) allocator {
+	return func(ctx context.Context) (context.Context, context.CancelFunc) {
+		return chromedp.NewRemoteAllocator(ctx, url)
+	}
+}
+
+func (s *browserSession) tab(ctx context.Context, opts ...chromedp.ContextOption) (context.Context, context.CancelFunc, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.browserCtx != nil && s.browserCtx.Err() != nil {
+		s.logger.Warn("Browser is gone, restarting")
+		if err := s.stop(); err != nil {
+			s.logger.Warn("Failed to clean up crashed browser", zap.Error(err))
+		}
+	}
+
+	if s.browserCtx == nil {
+		if err := s.start(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tabCtx, cancel := chromedp.NewContext(s.browserCtx, opts...)
+	stop := context.AfterFunc(ctx, cancel)
+
+	return tabCtx, func() {
+		stop()
+		cancel()
+	}, nil
+}
+
+func (s *browserSession) crashed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.browserCtx != nil && s.browserCtx.Err() != nil
+}
+
+func (s *browserSession) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.stop()
+}
+
+func (s *browserSession) start(ctx context.Context) error {
+	allocCtx, allocCancel := s.allocate(ctx)
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+
+	// the first Run on the root context launches the browser
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return fmt.Errorf("failed to start browser: %w", err)
+	}
+
+	s.allocCancel = allocCancel
+	s.browserCtx = browserCtx
+	s.browserCancel = browserCancel
+
+	if s.supervisor != nil {
+		s.supervisor.track(browserCtx)
+	}
+
+	s.logger.Info("Browser started")
+	return nil
+}
+
+func (s *browserSession) stop() error {
+	if s.browserCtx == nil {
+		return nil
+	}
+
+	var processes []process
+	if s.supervisor != nil {
+		var treeErr error
+		// the process groups are killed without the list
+		if processes, treeErr = s.supervisor.tree(); treeErr != nil && !errors.Is(treeErr, errNoProcessList) {
+			s.logger.Warn("Failed to list browser processes", zap.Error(treeErr))
+		}
+	}
+
+	var err error
+	if s.browserCtx.Err() == nil {
+		ctx, cancel := context.WithTimeout(s.browserCtx, browserCloseTimeout)
+		err = chromedp.Cancel(ctx)
+		cancel()
+	}
+	s.browserCancel()
+	s.allocCancel()
+
+	s.browserCtx = nil
+	s.browserCancel = nil
+	s.allocCancel = nil
+
+	if s.supervisor != nil {
+		s.supervisor.reap(processes)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to close browser: %w", err)
+	}
+
+	s.logger.Info("Browser stopped")
+	return nil
+}
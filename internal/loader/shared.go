@@ -0,0 +1,57 @@
+package loader
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// SharedLoader lets several sources load through one loader, e.g. one
+	// browser. The loader is closed when the last of them closes its handle.
+	SharedLoader struct {
+		mu    sync.Mutex
+		inner HtmlLoader
+		refs  int
+	}
+
+	sharedHandle struct {
+		shared *SharedLoader
+		once   sync.Once
+	}
+)
+
+func NewSharedLoader(inner HtmlLoader) *SharedLoader {
+	return &SharedLoader{inner: inner}
+}
+
+// Acquire returns a handle of the loader for one more user.
+func (s *SharedLoader) Acquire() HtmlLoader {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refs++
+	return &sharedHandle{shared: s}
+}
+
+func (s *SharedLoader) release() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refs--
+	if s.refs > 0 {
+		return nil
+	}
+	return s.inner.Close()
+}
+
+func (h *sharedHandle) Load(url string, ctx context.Context) (string, error) {
+	return h.shared.inner.Load(url, ctx)
+}
+
+func (h *sharedHandle) Close() error {
+	var err error
+	h.once.Do(func() {
+		err = h.shared.release()
+	})
+	return err
+}
@@ -0,0 +1,291 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/chromedp"
+	"go.uber.org/zap"
+)
+
+// errNoProcessList is returned by listProcesses where there is no /proc to
+// read and the build has no ps tag.
+var errNoProcessList = errors.New("process listing needs /proc or a build with the ps tag")
+
+// supervisor keeps track of the browsers started by a session so that none of
+// their processes outlive it, and kills the browsers a previous run of the
+// application started on the same user data folder and left behind.
+//
+// Every browser runs in a process group of its own, killing the group kills
+// the helpers it spawned as well. The pids are kept in a file next to the
+// user data folder for the next run. Resource usage is read from /proc on
+// linux and with ps in builds with the ps tag.
+type supervisor struct {
+	mu             sync.Mutex
+	userDataFolder string
+	pidFile        string
+	pids           map[int]struct{}
+	statsOff       sync.Once
+	logger         *zap.Logger
+}
+
+type process struct {
+	pid     int
+	ppid    int
+	rss     int64 // in KB
+	cpu     float64
+	command string
+}
+
+func newSupervisor(userDataFolder string, logger *zap.Logger) *supervisor {
+	return &supervisor{
+		userDataFolder: userDataFolder,
+		pidFile:        filepath.Clean(userDataFolder) + ".pids",
+		pids:           make(map[int]struct{}),
+		logger:         logger,
+	}
+}
+
+// track remembers the process the allocator started for the browser behind
+// browserCtx.
+func (s *supervisor) track(browserCtx context.Context) {
+	c := chromedp.FromContext(browserCtx)
+	if c == nil || c.Browser == nil || c.Browser.Process() == nil {
+		return
+	}
+
+	pid := c.Browser.Process().Pid
+
+	s.mu.Lock()
+	s.pids[pid] = struct{}{}
+	s.savePids()
+	s.mu.Unlock()
+
+	s.logger.Debug("Tracking browser process", zap.Int("pid", pid))
+}
+
+// savePids writes the tracked pids to the pid file, s.mu must be held.
+func (s *supervisor) savePids() {
+	if len(s.pids) == 0 {
+		if err := os.Remove(s.pidFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			s.logger.Warn("Failed to remove browser pid file", zap.String("path", s.pidFile), zap.Error(err))
+		}
+		return
+	}
+
+	lines := make([]string, 0, len(s.pids))
+	for pid := range s.pids {
+		lines = append(lines, strconv.Itoa(pid))
+	}
+	slices.Sort(lines)
+
+	if err := os.WriteFile(s.pidFile, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		s.logger.Warn("Failed to write browser pid file", zap.String("path", s.pidFile), zap.Error(err))
+	}
+}
+
+// tree lists the tracked browsers and all of their descendants.
+func (s *supervisor) tree() ([]process, error) {
+	all, err := listProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	roots := make(map[int]bool, len(s.pids))
+	for pid := range s.pids {
+		roots[pid] = true
+	}
+	s.mu.Unlock()
+
+	return descendants(all, roots), nil
+}
+
+// reap kills the process groups of the tracked browsers and forgets them,
+// along with the processes of before that left the groups and still run.
+// before is taken while the browsers are alive, their children are
+// reparented once they exit and cannot be found by their parent anymore.
+func (s *supervisor) reap(before []process) {
+	s.mu.Lock()
+	pids := make([]int, 0, len(s.pids))
+	for pid := range s.pids {
+		pids = append(pids, pid)
+	}
+	clear(s.pids)
+	s.savePids()
+	s.mu.Unlock()
+
+	for _, pid := range pids {
+		killed, err := killGroup(pid)
+		if err != nil {
+			s.logger.Warn("Failed to kill browser processes", zap.Int("pid", pid), zap.Error(err))
+			continue
+		}
+		if killed {
+			s.logger.Info("Killed leftover browser processes", zap.Int("pid", pid))
+		}
+	}
+
+	if len(before) == 0 {
+		return
+	}
+
+	running, err := listProcesses()
+	if err != nil {
+		s.logger.Warn("Failed to list processes", zap.Error(err))
+		return
+	}
+
+	alive := make(map[int]string, len(running))
+	for _, p := range running {
+		alive[p.pid] = p.command
+	}
+
+	for _, p := range before {
+		// a reused pid belongs to another process
+		if command, ok := alive[p.pid]; !ok || command != p.command {
+			continue
+		}
+		if err := kill(p.pid); err != nil {
+			s.logger.Warn("Failed to kill browser process", zap.Int("pid", p.pid), zap.Error(err))
+			continue
+		}
+		s.logger.Info("Killed leftover browser process", zap.Int("pid", p.pid))
+	}
+}
+
+// killStale kills the browsers the pid file of a previous run lists. A pid
+// is only trusted while it holds the lock of the user data folder or of a
+// temporary profile inside it, so neither a reused pid nor a browser of
+// another folder is killed.
+func (s *supervisor) killStale() error {
+	data, err := os.ReadFile(s.pidFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read browser pid file: %w", err)
+	}
+
+	locks := s.profileLocks()
+	for _, field := range strings.Fields(string(data)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil || pid == os.Getpid() || !locks[pid] {
+			continue
+		}
+
+		killed, err := killGroup(pid)
+		if err != nil {
+			s.logger.Warn("Failed to kill stale browser", zap.Int("pid", pid), zap.Error(err))
+			continue
+		}
+		if killed {
+			s.logger.Warn("Killed stale browser", zap.Int("pid", pid))
+		}
+	}
+
+	if err := os.Remove(s.pidFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove browser pid file: %w", err)
+	}
+	return nil
+}
+
+// profileLocks returns the pids of the browsers holding the user data folder
+// and its temporary profiles. Chrome links SingletonLock in a profile it uses
+// to "<host>-<pid>".
+func (s *supervisor) profileLocks() map[int]bool {
+	profiles, _ := filepath.Glob(filepath.Join(s.userDataFolder, temporaryProfilePattern))
+	host, _ := os.Hostname()
+
+	locks := make(map[int]bool)
+	for _, profile := range append([]string{s.userDataFolder}, profiles...) {
+		target, err := os.Readlink(filepath.Join(profile, "SingletonLock"))
+		if err != nil {
+			continue
+		}
+
+		sep := strings.LastIndex(target, "-")
+		if sep < 0 || target[:sep] != host {
+			continue
+		}
+		if pid, err := strconv.Atoi(target[sep+1:]); err == nil {
+			locks[pid] = true
+		}
+	}
+	return locks
+}
+
+// logStats logs the memory and cpu used by the tracked browsers. When the
+// processes can't be listed that is told once.
+func (s *supervisor) logStats() {
+	processes, err := s.tree()
+	if err != nil {
+		s.statsOff.Do(func() {
+			s.logger.Warn("Browser resource usage is not logged", zap.Error(err))
+		})
+		return
+	}
+	if len(processes) == 0 {
+		return
+	}
+
+	var rss int64
+	var cpu float64
+	for _, p := range processes {
+		rss += p.rss
+		cpu += p.cpu
+	}
+
+	s.logger.Info("Browser resource usage",
+		zap.Int("processes", len(processes)),
+		zap.Int64("rss_mb", rss>>10),
+		zap.Float64("cpu_percent", cpu))
+}
+
+// descendants returns the processes of roots and everything they spawned.
+func descendants(all []process, roots map[int]bool) []process {
+	children := make(map[int][]process)
+	byPid := make(map[int]process, len(all))
+	for _, p := range all {
+		children[p.ppid] = append(children[p.ppid], p)
+		byPid[p.pid] = p
+	}
+
+	var found []process
+	seen := make(map[int]bool)
+	var walk func(p process)
+	walk = func(p process) {
+		if seen[p.pid] {
+			return
+		}
+		seen[p.pid] = true
+		found = append(found, p)
+		for _, child := range children[p.pid] {
+			walk(child)
+		}
+	}
+
+	for pid := range roots {
+		if p, ok := byPid[pid]; ok {
+			walk(p)
+		}
+	}
+
+	return found
+}
+
+func kill(pid int) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return p.Kill()
+}
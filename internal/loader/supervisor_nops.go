@@ -0,0 +1,7 @@
+//go:build !ps && !linux
+
+package loader
+
+func listProcesses() ([]process, error) {
+	return nil, errNoProcessList
+}
@@ -0,0 +1,134 @@
+//go:build linux && !ps
+
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// clockTicks is USER_HZ, the unit of the times in /proc/<pid>/stat. It is 100
+// on every architecture Go supports.
+const clockTicks = 100
+
+// listProcesses reads the running processes from /proc. The cpu usage is the
+// cpu time of a process over its lifetime, as ps reports it.
+func listProcesses() ([]process, error) {
+	uptime, err := readUptime()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	var processes []process
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		// a process may exit while it is read
+		p, err := readProcess(pid, uptime)
+		if err != nil {
+			continue
+		}
+		processes = append(processes, p)
+	}
+
+	return processes, nil
+}
+
+func readProcess(pid int, uptime float64) (process, error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return process{}, err
+	}
+
+	// the command name is in parentheses and may hold spaces and parentheses
+	open, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return process{}, fmt.Errorf("malformed %s/stat", dir)
+	}
+	name := string(stat[open+1 : end])
+
+	// fields from the state on, the state is the third field of the file
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 20 {
+		return process{}, fmt.Errorf("malformed %s/stat", dir)
+	}
+	ppid, ppidErr := strconv.Atoi(fields[1])
+	utime, utimeErr := strconv.ParseFloat(fields[11], 64)
+	stime, stimeErr := strconv.ParseFloat(fields[12], 64)
+	start, startErr := strconv.ParseFloat(fields[19], 64)
+	if ppidErr != nil || utimeErr != nil || stimeErr != nil || startErr != nil {
+		return process{}, fmt.Errorf("malformed %s/stat", dir)
+	}
+
+	var cpu float64
+	if elapsed := uptime - start/clockTicks; elapsed > 0 {
+		cpu = (utime + stime) / clockTicks / elapsed * 100
+	}
+
+	rss, err := readRss(dir)
+	if err != nil {
+		return process{}, err
+	}
+
+	command := name
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil && len(cmdline) > 0 {
+		command = strings.Join(strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00"), " ")
+	}
+
+	return process{
+		pid:     pid,
+		ppid:    ppid,
+		rss:     rss,
+		cpu:     cpu,
+		command: command,
+	}, nil
+}
+
+// readRss returns the resident memory in KB, 0 for processes without memory
+// of their own.
+func readRss(dir string) (int64, error) {
+	status, err := os.ReadFile(filepath.Join(dir, "status"))
+	if err != nil {
+		return 0, err
+	}
+
+	for line := range strings.Lines(string(status)) {
+		value, ok := strings.CutPrefix(line, "VmRSS:")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			break
+		}
+		return strconv.ParseInt(fields[0], 10, 64)
+	}
+	return 0, nil
+}
+
+func readUptime() (float64, error) {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read uptime: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("malformed /proc/uptime")
+	}
+	return strconv.ParseFloat(fields[0], 64)
+}
@@ -0,0 +1,26 @@
+//go:build linux && !ps
+
+package loader
+
+import (
+	"os"
+	"testing"
+)
+
+func TestListProcesses(t *testing.T) {
+	processes, err := listProcesses()
+	if err != nil {
+		t.Fatalf("listProcesses() error = %v", err)
+	}
+
+	for _, p := range processes {
+		if p.pid != os.Getpid() {
+			continue
+		}
+		if p.ppid != os.Getppid() || p.rss <= 0 || p.command == "" {
+			t.Errorf("listProcesses() = %+v for this process", p)
+		}
+		return
+	}
+	t.Errorf("listProcesses() misses this process")
+}
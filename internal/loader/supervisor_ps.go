@@ -0,0 +1,49 @@
+//go:build ps
+
+package loader
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// listProcesses reads the running processes with ps, the browser tree is
+// listed and its resource usage logged with them.
+func listProcesses() ([]process, error) {
+	out, err := exec.Command("ps", "-axo", "pid=,ppid=,rss=,pcpu=,command=").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	var processes []process
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		pid, pidErr := strconv.Atoi(fields[0])
+		ppid, ppidErr := strconv.Atoi(fields[1])
+		rss, rssErr := strconv.ParseInt(fields[2], 10, 64)
+		cpu, cpuErr := strconv.ParseFloat(fields[3], 64)
+		if pidErr != nil || ppidErr != nil || rssErr != nil || cpuErr != nil {
+			continue
+		}
+
+		processes = append(processes, process{
+			pid:     pid,
+			ppid:    ppid,
+			rss:     rss,
+			cpu:     cpu,
+			command: strings.Join(fields[4:], " "),
+		})
+	}
+
+	return processes, scanner.Err()
+}
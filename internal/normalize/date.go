@@ -0,0 +1,181 @@
+package normalize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type relativeUnit struct {
+	stems []string
+	shift func(now time.Time, n int) time.Time
+}
+
+// relativeUnits are matched by the stem of the unit word, so every plural
+// form of "2 дні", "5 днів", "2 дня" and "5 дней" is covered. Years come
+// first, the russian "года" starts like the ukrainian "год" of hours.
+var relativeUnits = []relativeUnit{
+	{[]string{"рік", "рок", "лет", "года", "year"}, yearsAgo},
+	{[]string{"хв", "мин", "min"}, func(now time.Time, n int) time.Time {
+		return now.Add(-time.Duration(n) * time.Minute)
+	}},
+	{[]string{"год", "час", "hour", "hr"}, func(now time.Time, n int) time.Time {
+		return now.Add(-time.Duration(n) * time.Hour)
+	}},
+	{[]string{"дн", "день", "дня", "дней", "day"}, func(now time.Time, n int) time.Time {
+		return startOfDay(now).AddDate(0, 0, -n)
+	}},
+	{[]string{"тиж", "недел", "week"}, func(now time.Time, n int) time.Time {
+		return startOfDay(now).AddDate(0, 0, -7*n)
+	}},
+	{[]string{"місяц", "месяц", "month"}, func(now time.Time, n int) time.Time {
+		return startOfDay(now).AddDate(0, -n, 0)
+	}},
+}
+
+func yearsAgo(now time.Time, n int) time.Time {
+	return startOfDay(now).AddDate(-n, 0, 0)
+}
+
+var months = map[string]time.Month{
+	"січ": time.January, "лют": time.February, "бер": time.March, "кві": time.April,
+	"тра": time.May, "чер": time.June, "лип": time.July, "сер": time.August,
+	"вер": time.September, "жов": time.October, "лис": time.November, "гру": time.December,
+
+	"янв": time.January, "фев": time.February, "мар": time.March, "апр": time.April,
+	"мая": time.May, "май": time.May, "июн": time.June, "июл": time.July, "авг": time.August,
+	"сен": time.September, "окт": time.October, "ноя": time.November, "дек": time.December,
+
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+var (
+	todayWords     = []string{"сьогодні", "сегодня", "today", "щойно", "только что", "just now", "зараз", "сейчас"}
+	yesterdayWords = []string{"вчора", "вчера", "yesterday"}
+	agoWords       = []string{"тому", "назад", "ago"}
+
+	relativePattern = regexp.MustCompile(`(?:(\d+)\+?\s*)?([\p{L}]+)\s+(тому|назад|ago)`)
+	dayMonthPattern = regexp.MustCompile(`(\d{1,2})\s+([\p{L}]+)\.?(?:\s+(\d{4}))?`)
+	monthDayPattern = regexp.MustCompile(`([\p{L}]+)\.?\s+(\d{1,2}),?(?:\s+(\d{4}))?`)
+	numericPattern  = regexp.MustCompile(`(\d{1,2})\.(\d{1,2})\.(\d{4})`)
+	isoPattern      = regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`)
+)
+
+// ParseDate turns a posting date such as "2 дні тому", "Сьогодні",
+// "1 тиждень тому" or "24 жовтня 2025" into a time in the location of now,
+// the time the page was fetched. Dates given by day are taken at midnight.
+func ParseDate(raw string, now time.Time) (time.Time, bool) {
+	text := strings.ToLower(strings.Join(strings.Fields(raw), " "))
+	if text == "" {
+		return time.Time{}, false
+	}
+
+	if containsAny(text, yesterdayWords) {
+		return startOfDay(now).AddDate(0, 0, -1), true
+	}
+	if containsAny(text, todayWords) {
+		return startOfDay(now), true
+	}
+
+	if containsAny(text, agoWords) {
+		if posted, ok := parseRelative(text, now); ok {
+			return posted, true
+		}
+	}
+
+	return parseAbsolute(text, now)
+}
+
+func parseRelative(text string, now time.Time) (time.Time, bool) {
+	match := relativePattern.FindStringSubmatch(text)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	// "годину тому" and "an hour ago" mean one
+	n := 1
+	if match[1] != "" {
+		n, _ = strconv.Atoi(match[1])
+	}
+
+	// "год" is the russian year of "1 год назад" and the ukrainian hours of
+	// "3 год тому"
+	word := match[2]
+	if word == "год" && match[3] == "назад" {
+		return yearsAgo(now, n), true
+	}
+	for _, unit := range relativeUnits {
+		for _, stem := range unit.stems {
+			if strings.HasPrefix(word, stem) {
+				return unit.shift(now, n), true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func parseAbsolute(text string, now time.Time) (time.Time, bool) {
+	if match := isoPattern.FindStringSubmatch(text); match != nil {
+		return date(now, match[1], monthNumber(match[2]), match[3])
+	}
+	if match := numericPattern.FindStringSubmatch(text); match != nil {
+		return date(now, match[3], monthNumber(match[2]), match[1])
+	}
+	if match := dayMonthPattern.FindStringSubmatch(text); match != nil {
+		if month, ok := monthName(match[2]); ok {
+			return date(now, match[3], month, match[1])
+		}
+	}
+	if match := monthDayPattern.FindStringSubmatch(text); match != nil {
+		if month, ok := monthName(match[1]); ok {
+			return date(now, match[3], month, match[2])
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// date builds a date in the location of now. A date without a year is the
+// last such date that is not in the future.
+func date(now time.Time, year string, month time.Month, day string) (time.Time, bool) {
+	d, err := strconv.Atoi(day)
+	if err != nil || month < time.January || month > time.December || d < 1 || d > 31 {
+		return time.Time{}, false
+	}
+
+	if year == "" {
+		posted := time.Date(now.Year(), month, d, 0, 0, 0, 0, now.Location())
+		if posted.After(now) {
+			posted = posted.AddDate(-1, 0, 0)
+		}
+		return posted, true
+	}
+
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Date(y, month, d, 0, 0, 0, 0, now.Location()), true
+}
+
+func monthName(word string) (time.Month, bool) {
+	runes := []rune(word)
+	if len(runes) < 3 {
+		return 0, false
+	}
+	month, ok := months[string(runes[:3])]
+	return month, ok
+}
+
+func monthNumber(number string) time.Month {
+	month, _ := strconv.Atoi(number)
+	return time.Month(month)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
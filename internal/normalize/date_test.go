@@ -0,0 +1,61 @@
+package normalize
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	kyiv, err := time.LoadLocation("Europe/Kyiv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, time.October, 24, 15, 30, 0, 0, kyiv)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, kyiv)
+	}
+
+	tests := []struct {
+		raw  string
+		want time.Time
+	}{
+		{"Сьогодні", day(2025, time.October, 24)},
+		{"вчера", day(2025, time.October, 23)},
+		{"2 дні тому", day(2025, time.October, 22)},
+		{"5 дней назад", day(2025, time.October, 19)},
+		{"1 тиждень тому", day(2025, time.October, 17)},
+		{"годину тому", now.Add(-time.Hour)},
+		{"3 год тому", now.Add(-3 * time.Hour)},
+		{"2 часа назад", now.Add(-2 * time.Hour)},
+		{"15 хв тому", now.Add(-15 * time.Minute)},
+		{"1 год назад", day(2024, time.October, 24)},
+		{"2 года назад", day(2023, time.October, 24)},
+		{"1 рік тому", day(2024, time.October, 24)},
+		{"30+ days ago", day(2025, time.September, 24)},
+		{"24 жовтня 2025", day(2025, time.October, 24)},
+		{"3 ноября", day(2024, time.November, 3)},
+		{"Oct 20, 2025", day(2025, time.October, 20)},
+		{"20.10.2025", day(2025, time.October, 20)},
+		{"2025-10-20", day(2025, time.October, 20)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, ok := ParseDate(tt.raw, now)
+			if !ok {
+				t.Fatalf("ParseDate(%q) failed", tt.raw)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateUnknown(t *testing.T) {
+	for _, raw := range []string{"", "терміново", "нещодавно"} {
+		if got, ok := ParseDate(raw, time.Now()); ok {
+			t.Errorf("ParseDate(%q) = %v, want no date", raw, got)
+		}
+	}
+}
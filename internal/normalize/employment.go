@@ -0,0 +1,72 @@
+package normalize
+
+import (
+	"jooble-parser/internal/domain"
+	"regexp"
+	"strings"
+)
+
+// The phrases are checked in order, so the more specific ones come first:
+// "неповна зайнятість" contains "повна зайнятість" and hybrid jobs mention
+// remote work. Latin words are matched as whole words, "intern" must not
+// match "international".
+var employmentPhrases = []struct {
+	employment domain.EmploymentType
+	pattern    *regexp.Regexp
+}{
+	{domain.EmploymentInternship, phrases(`стажуван`, `стажер`, `стажист`, `стажёр`, `стажиров`, `\bintern(ship)?s?\b`, `\btrainee\b`)},
+	{domain.EmploymentPartTime, phrases(`неповн`, `часткова зайнят`, `частичная занят`, `неполн`, `\bpart[- ]?time\b`)},
+	{domain.EmploymentContract, phrases(`контракт`, `договір підряду`, `договор подряда`, `цпх`, `гпх`, `фріланс`, `фриланс`, `проєктна робота`, `проектная работа`, `\bfreelance\b`, `\bcontract(or)?\b`, `\bb2b\b`)},
+	{domain.EmploymentFullTime, phrases(`повна зайнят`, `повний робочий`, `повний день`, `полная занят`, `полный рабочий`, `полный день`, `\bfull[- ]?time\b`)},
+}
+
+var workModePhrases = []struct {
+	mode    domain.WorkMode
+	pattern *regexp.Regexp
+}{
+	{domain.WorkModeHybrid, phrases(`гібрид`, `гибрид`, `частково віддал`, `частично удал`, `\bhybrid\b`)},
+	{domain.WorkModeRemote, phrases(`віддален`, `дистанційн`, `удалён`, `удален`, `дистанционн`, `з дому`, `из дома`, `\bremote(ly)?\b`, `\bwork from home\b`, `\bwfh\b`)},
+	{domain.WorkModeOnsite, phrases(`в офісі`, `робота в офіс`, `в офисе`, `работа в офис`, `\bon[- ]?site\b`, `\bin[- ]office\b`)},
+}
+
+func phrases(alternatives ...string) *regexp.Regexp {
+	return regexp.MustCompile(strings.Join(alternatives, "|"))
+}
+
+// ParseEmployment reads the employment type and work mode of job. The work
+// type, tags and city shown on the card are trusted first, the title and
+// descriptions are only searched for what they don't state.
+func ParseEmployment(job *domain.Job) (domain.EmploymentType, domain.WorkMode) {
+	stated := strings.ToLower(strings.Join(append([]string{job.WorkType, job.City}, job.Tags...), "\n"))
+	described := strings.ToLower(strings.Join([]string{job.Title, job.Description, job.FullDescription}, "\n"))
+
+	employment := matchEmployment(stated)
+	if employment == "" {
+		employment = matchEmployment(described)
+	}
+
+	mode := matchWorkMode(stated)
+	if mode == "" {
+		mode = matchWorkMode(described)
+	}
+
+	return employment, mode
+}
+
+func matchEmployment(text string) domain.EmploymentType {
+	for _, entry := range employmentPhrases {
+		if entry.pattern.MatchString(text) {
+			return entry.employment
+		}
+	}
+	return ""
+}
+
+func matchWorkMode(text string) domain.WorkMode {
+	for _, entry := range workModePhrases {
+		if entry.pattern.MatchString(text) {
+			return entry.mode
+		}
+	}
+	return ""
+}
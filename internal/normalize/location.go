@@ -0,0 +1,193 @@
+package normalize
+
+import (
+	_ "embed"
+	"fmt"
+	"jooble-parser/internal/domain"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed gazetteer.yml
+var gazetteerFile []byte
+
+type (
+	place struct {
+		Name    string   `yaml:"name"`
+		Code    string   `yaml:"code"`    // countries only
+		Region  string   `yaml:"region"`  // cities only
+		Country string   `yaml:"country"` // code of the country of cities and regions
+		Aliases []string `yaml:"aliases"`
+	}
+
+	gazetteerSpec struct {
+		Countries []place `yaml:"countries"`
+		Regions   []place `yaml:"regions"`
+		Cities    []place `yaml:"cities"`
+	}
+
+	// gazetteer finds places by any of their aliases.
+	gazetteer struct {
+		countries map[string]place
+		regions   map[string]place
+		cities    map[string]place
+	}
+)
+
+var places = mustLoadGazetteer(gazetteerFile)
+
+func mustLoadGazetteer(data []byte) *gazetteer {
+	g, err := loadGazetteer(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid gazetteer: %v", err))
+	}
+	return g
+}
+
+func loadGazetteer(data []byte) (*gazetteer, error) {
+	var spec gazetteerSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	g := &gazetteer{
+		countries: make(map[string]place),
+		regions:   make(map[string]place),
+		cities:    make(map[string]place),
+	}
+	codes := make(map[string]bool)
+
+	for _, country := range spec.Countries {
+		if err := index(g.countries, country); err != nil {
+			return nil, err
+		}
+		codes[country.Code] = true
+	}
+	for _, region := range spec.Regions {
+		if !codes[region.Country] {
+			return nil, fmt.Errorf("region %q: unknown country %q", region.Name, region.Country)
+		}
+		if err := index(g.regions, region); err != nil {
+			return nil, err
+		}
+	}
+	for _, city := range spec.Cities {
+		if !codes[city.Country] {
+			return nil, fmt.Errorf("city %q: unknown country %q", city.Name, city.Country)
+		}
+		if err := index(g.cities, city); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+func index(places map[string]place, p place) error {
+	for _, alias := range append([]string{p.Name, p.Code}, p.Aliases...) {
+		key := aliasKey(alias)
+		if key == "" {
+			continue
+		}
+		if other, ok := places[key]; ok && other.Name != p.Name {
+			return fmt.Errorf("alias %q of %q is taken by %q", alias, p.Name, other.Name)
+		}
+		places[key] = p
+	}
+	return nil
+}
+
+var (
+	// aliasPrefixes are stripped before a name is looked up, "м. Київ" is Kyiv.
+	aliasPrefixes = []string{"м.", "г.", "місто ", "город ", "city of "}
+
+	aliasReplacer = strings.NewReplacer("’", "'", "ʼ", "'", "`", "'", "обл.", "область")
+)
+
+// aliasKey is the form names are compared in: lower case, single spaced and
+// with one kind of apostrophe.
+func aliasKey(name string) string {
+	key := strings.Join(strings.Fields(strings.ToLower(name)), " ")
+	key = aliasReplacer.Replace(key)
+	if strings.HasSuffix(key, " обл") {
+		key += "асть"
+	}
+	for _, prefix := range aliasPrefixes {
+		key = strings.TrimSpace(strings.TrimPrefix(key, prefix))
+	}
+	return strings.Trim(key, " .")
+}
+
+// CanonicalCity returns the canonical name of the city called name in any
+// language the gazetteer knows.
+func CanonicalCity(name string) (string, bool) {
+	city, ok := places.cities[aliasKey(name)]
+	return city.Name, ok
+}
+
+// CanonicalCountry returns the code of the country called or coded name.
+func CanonicalCountry(name string) (string, bool) {
+	country, ok := places.countries[aliasKey(name)]
+	return country.Code, ok
+}
+
+var (
+	remotePhrases = phrases(`віддален`, `дистанційн`, `удалён`, `удален`, `дистанционн`, `\bremote\b`)
+
+	relocationPhrases = phrases(`переїзд`, `переезд`, `релокац`, `\brelocat(e|ion)\b`, `\bvisa sponsorship\b`)
+
+	// jobs placed abroad are offered to people who would move there
+	abroadPhrases = phrases(`за кордон`, `за границ`, `за рубеж`, `\babroad\b`)
+)
+
+// ParseLocation reads where job is done from the city shown on the card,
+// e.g. "Київ, Київська область" or "Варшава, Польща". Whether the job is
+// remote comes from the city and tags, whether it offers relocation also
+// from the title and descriptions.
+func ParseLocation(job *domain.Job) domain.Location {
+	var location domain.Location
+
+	for _, part := range strings.FieldsFunc(job.City, isPlaceSeparator) {
+		key := aliasKey(part)
+
+		if city, ok := places.cities[key]; ok && location.City == "" {
+			location.City = city.Name
+			if location.Region == "" {
+				location.Region = city.Region
+			}
+			if location.Country == "" {
+				location.Country = city.Country
+			}
+			continue
+		}
+		if region, ok := places.regions[key]; ok && location.Region == "" {
+			location.Region = region.Name
+			if location.Country == "" {
+				location.Country = region.Country
+			}
+			continue
+		}
+		if country, ok := places.countries[key]; ok && location.Country == "" {
+			location.Country = country.Code
+		}
+	}
+
+	stated := strings.ToLower(strings.Join(append([]string{job.City}, job.Tags...), "\n"))
+	described := strings.ToLower(strings.Join([]string{job.Title, job.Description, job.FullDescription}, "\n"))
+
+	location.Remote = remotePhrases.MatchString(stated) && !matchesHybrid(stated)
+	location.Relocation = relocationPhrases.MatchString(stated) ||
+		abroadPhrases.MatchString(stated) ||
+		relocationPhrases.MatchString(described)
+
+	return location
+}
+
+func matchesHybrid(text string) bool {
+	return matchWorkMode(text) == domain.WorkModeHybrid
+}
+
+func isPlaceSeparator(r rune) bool {
+	return r == ',' || r == ';' || r == '(' || r == ')' || r == '/' || r == '\n'
+}
@@ -0,0 +1,43 @@
+package normalize
+
+import (
+	"jooble-parser/internal/domain"
+	"time"
+)
+
+// Normalizer derives the structured fields of jobs from their scraped text.
+type Normalizer struct {
+	location *time.Location
+}
+
+// NewNormalizer reads relative and day-only dates in location.
+func NewNormalizer(location *time.Location) *Normalizer {
+	return &Normalizer{location: location}
+}
+
+func (n *Normalizer) Normalize(jobs []domain.Job) []domain.Job {
+	fetched := time.Now().In(n.location)
+
+	for i := range jobs {
+		job := &jobs[i]
+
+		// structured data, e.g. a JobPosting, is kept over what the text says
+		if !job.Pay.Known() {
+			job.Pay = ParseSalary(job.Salary)
+		}
+		if job.PostedAt.IsZero() {
+			if posted, ok := ParseDate(job.Date, fetched); ok {
+				job.PostedAt = posted
+			}
+		}
+		employment, mode := ParseEmployment(job)
+		if job.Employment == "" {
+			job.Employment = employment
+		}
+		if job.WorkMode == "" {
+			job.WorkMode = mode
+		}
+		job.Location = ParseLocation(job)
+	}
+	return jobs
+}
@@ -0,0 +1,188 @@
+package normalize
+
+import (
+	"jooble-parser/internal/domain"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// amountPattern matches 40000, 40 000, 40,000, 40.000 and 15.5
+	// a multiplier is followed by anything but a letter, "2 курси" has none
+	amountPattern = regexp.MustCompile(`\d+(?:[ .,]\d{3})*(?:[.,]\d{1,2})?(?:\s*(?:k|к|тис\.?|тыс\.?)(?:\s|$|[^\p{L}]))?`)
+	thousands     = regexp.MustCompile(`^\d{1,3}(?:[ .,]\d{3})+$`)
+	multiplier    = regexp.MustCompile(`\s*(?:k|к|тис|тыс)\.?$`)
+)
+
+var currencyWords = []struct {
+	currency string
+	words    []string
+}{
+	{domain.CurrencyUAH, []string{"грн", "₴", "uah", "гривен", "гривень", "гривн", "hryvn"}},
+	{domain.CurrencyUSD, []string{"$", "usd", "долар", "доллар", "dollar"}},
+	{domain.CurrencyEUR, []string{"€", "eur", "євро", "евро", "euro"}},
+}
+
+var periodWords = []struct {
+	period string
+	words  []string
+}{
+	{domain.PeriodHour, []string{"/год", "год.", "за годину", "на годину", "в годину", "погодин", "/час", "в час", "за час", "почасов", "/hour", "/hr", "per hour", "an hour", "hourly", "/h"}},
+	{domain.PeriodYear, []string{"/рік", "на рік", "за рік", "в рік", "/год.", "в год", "за год ", "/year", "/yr", "per year", "a year", "annual", "yearly"}},
+	{domain.PeriodMonth, []string{"/міс", "на місяць", "за місяць", "в місяць", "щоміся", "/мес", "в месяц", "за месяц", "ежемесяч", "/month", "/mo", "per month", "a month", "monthly"}},
+}
+
+var basisWords = []struct {
+	basis string
+	words []string
+}{
+	{domain.BasisNet, []string{"net", "нетто", "на руки", "чистими", "чистыми", "після сплати податків", "після вирахування податків", "после вычета налогов", "после уплаты налогов"}},
+	{domain.BasisGross, []string{"gross", "брутто", "до вирахування податків", "до сплати податків", "до оподаткування", "до вычета налогов", "до уплаты налогов", "before tax"}},
+}
+
+var (
+	fromWords = []string{"від", "от", "from", "starting", "min"}
+	upToWords = []string{"до", "up to", "to", "max"}
+)
+
+// ParseSalary reads the amounts, currency, period and tax basis from a salary
+// text such as "від 40 000 грн", "$2000–3000" or "150 грн/год".
+func ParseSalary(raw string) domain.Salary {
+	var salary domain.Salary
+
+	text := strings.ToLower(raw)
+	text = strings.NewReplacer(" ", " ", " ", " ", " ", " ", " ", " ").Replace(text)
+
+	amounts := amountPattern.FindAllStringIndex(text, -1)
+	if len(amounts) == 0 {
+		return salary
+	}
+
+	var values []amount
+	for _, loc := range amounts {
+		if value := parseAmount(text[loc[0]:loc[1]]); value.value > 0 {
+			values = append(values, value)
+		}
+	}
+	if len(values) == 0 {
+		return salary
+	}
+	if len(values) >= 2 {
+		shareMultiplier(&values[0], &values[1])
+	}
+
+	switch {
+	case len(values) >= 2:
+		salary.Min, salary.Max = values[0].whole(), values[1].whole()
+		if salary.Min > salary.Max {
+			salary.Min, salary.Max = salary.Max, salary.Min
+		}
+	case hasWordBefore(text, amounts[0][0], upToWords):
+		salary.Max = values[0].whole()
+	case hasWordBefore(text, amounts[0][0], fromWords):
+		salary.Min = values[0].whole()
+	default:
+		salary.Min, salary.Max = values[0].whole(), values[0].whole()
+	}
+
+	for _, entry := range currencyWords {
+		if containsAny(text, entry.words) {
+			salary.Currency = entry.currency
+			break
+		}
+	}
+	for _, entry := range periodWords {
+		if containsAny(text, entry.words) {
+			salary.Period = entry.period
+			break
+		}
+	}
+	for _, entry := range basisWords {
+		if containsAny(text, entry.words) {
+			salary.Basis = entry.basis
+			break
+		}
+	}
+
+	return salary
+}
+
+type amount struct {
+	value  float64
+	factor float64
+}
+
+func (a amount) whole() int64 {
+	return int64(math.Round(a.value * a.factor))
+}
+
+// parseAmount reads an amount matched by amountPattern along with the
+// separator that may follow its multiplier.
+func parseAmount(match string) amount {
+	match = strings.TrimRightFunc(match, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+	})
+
+	parsed := amount{factor: 1}
+	if loc := multiplier.FindStringIndex(match); loc != nil {
+		parsed.factor = 1000
+		match = match[:loc[0]]
+	}
+	match = strings.TrimRight(match, " ")
+
+	var number string
+	if thousands.MatchString(match) {
+		number = strings.NewReplacer(" ", "", ",", "", ".", "").Replace(match)
+	} else {
+		number = strings.ReplaceAll(strings.ReplaceAll(match, " ", ""), ",", ".")
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return amount{}
+	}
+	parsed.value = value
+	return parsed
+}
+
+// shareMultiplier applies the multiplier stated once at the end of a range,
+// as in "від 30 до 50 тис" or "2-3k", to the bare amount before it.
+func shareMultiplier(from, to *amount) {
+	if from.factor == 1 && from.value <= to.value {
+		from.factor = to.factor
+	}
+}
+
+// hasWordBefore reports whether one of words stands right before offset,
+// ignoring currency signs and spaces in between.
+func hasWordBefore(text string, offset int, words []string) bool {
+	before := strings.TrimRight(text[:offset], " $€₴")
+	for _, word := range words {
+		if !strings.HasSuffix(before, word) {
+			continue
+		}
+		start := len(before) - len(word)
+		if start == 0 || !isLetter(before[:start]) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLetter(prefix string) bool {
+	r := []rune(prefix)
+	last := r[len(r)-1]
+	return last >= 'a' && last <= 'z' || last >= 'а' && last <= 'я' || strings.ContainsRune("іїєґё", last)
+}
+
+func containsAny(text string, words []string) bool {
+	for _, word := range words {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
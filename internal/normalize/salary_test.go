@@ -0,0 +1,36 @@
+package normalize
+
+import (
+	"jooble-parser/internal/domain"
+	"testing"
+)
+
+func TestParseSalary(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want domain.Salary
+	}{
+		{"від 40 000 грн", domain.Salary{Min: 40000, Currency: domain.CurrencyUAH}},
+		{"до 50 000 грн", domain.Salary{Max: 50000, Currency: domain.CurrencyUAH}},
+		{"$2000–3000", domain.Salary{Min: 2000, Max: 3000, Currency: domain.CurrencyUSD}},
+		{"$2k-3k", domain.Salary{Min: 2000, Max: 3000, Currency: domain.CurrencyUSD}},
+		{"2k–3k $", domain.Salary{Min: 2000, Max: 3000, Currency: domain.CurrencyUSD}},
+		{"2-3k usd", domain.Salary{Min: 2000, Max: 3000, Currency: domain.CurrencyUSD}},
+		{"Зарплата від 30 до 50 тис грн", domain.Salary{Min: 30000, Max: 50000, Currency: domain.CurrencyUAH}},
+		{"від 30 тис. до 50 тис. грн", domain.Salary{Min: 30000, Max: 50000, Currency: domain.CurrencyUAH}},
+		{"500 - 2k $", domain.Salary{Min: 500, Max: 2000, Currency: domain.CurrencyUSD}},
+		{"1,5k €", domain.Salary{Min: 1500, Max: 1500, Currency: domain.CurrencyEUR}},
+		{"40.000 грн на руки", domain.Salary{Min: 40000, Max: 40000, Currency: domain.CurrencyUAH, Basis: domain.BasisNet}},
+		{"150 грн/год", domain.Salary{Min: 150, Max: 150, Currency: domain.CurrencyUAH, Period: domain.PeriodHour}},
+		{"$60k per year gross", domain.Salary{Min: 60000, Max: 60000, Currency: domain.CurrencyUSD, Period: domain.PeriodYear, Basis: domain.BasisGross}},
+		{"за домовленістю", domain.Salary{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if got := ParseSalary(tt.raw); got != tt.want {
+				t.Errorf("ParseSalary(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
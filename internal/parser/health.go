@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/parser/setters"
+	"slices"
+	"strings"
+)
+
+// ErrDegraded is returned for a page whose jobs look broken, most likely
+// because the markup changed and the selectors no longer match.
+var ErrDegraded = errors.New("parse degraded")
+
+// Health describes how well the selectors matched the cards of a page.
+type Health struct {
+	Cards    int                `json:"cards"`
+	FillRate map[string]float64 `json:"fill_rate"` // share of jobs with the field filled
+	Missing  []string           `json:"missing"`   // required fields below the minimal fill rate
+	Degraded bool               `json:"degraded"`
+}
+
+type healthCheck struct {
+	fields      []string
+	required    []string
+	minCards    int
+	minFillRate float64
+}
+
+func newHealthCheck(spec *setters.Spec, cfg *config.HealthConfig) *healthCheck {
+	check := &healthCheck{
+		minCards:    cfg.GetMinCards(),
+		minFillRate: cfg.GetMinFillRate(),
+	}
+
+	for _, field := range spec.Fields {
+		if !slices.Contains(check.fields, field.Field) {
+			check.fields = append(check.fields, field.Field)
+		}
+		if field.IsRequired() && !slices.Contains(check.required, field.Field) {
+			check.required = append(check.required, field.Field)
+		}
+	}
+
+	return check
+}
+
+func (c *healthCheck) report(cards int, jobs []domain.Job) *Health {
+	health := &Health{
+		Cards:    cards,
+		FillRate: make(map[string]float64, len(c.fields)),
+	}
+
+	for _, field := range c.fields {
+		if len(jobs) == 0 {
+			health.FillRate[field] = 0
+			continue
+		}
+
+		filled := 0
+		for i := range jobs {
+			if setters.Filled(&jobs[i], field) {
+				filled++
+			}
+		}
+		health.FillRate[field] = float64(filled) / float64(len(jobs))
+	}
+
+	for _, field := range c.required {
+		if health.FillRate[field] < c.minFillRate {
+			health.Missing = append(health.Missing, field)
+		}
+	}
+
+	health.Degraded = cards < c.minCards || len(health.Missing) > 0
+	return health
+}
+
+func (h *Health) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d cards", h.Cards)
+	for i, field := range h.Missing {
+		if i == 0 {
+			b.WriteString(", required fields missing: ")
+		} else {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s (%.0f%% filled)", field, h.FillRate[field]*100)
+	}
+	return b.String()
+}
@@ -0,0 +1,400 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"jooble-parser/internal/domain"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// JobPostings reads the schema.org JobPosting objects a page embeds as
+// JSON-LD or microdata, JSON-LD first. Fields a posting doesn't state are
+// left empty. The external id is the one of the posting, which needn't be
+// the one of its card.
+func JobPostings(doc *goquery.Document) []domain.Job {
+	var jobs []domain.Job
+
+	doc.Find(`script[type="application/ld+json"]`).Each(func(_ int, script *goquery.Selection) {
+		var data any
+		if err := json.Unmarshal([]byte(script.Text()), &data); err != nil {
+			return
+		}
+		for _, posting := range findPostings(data) {
+			jobs = append(jobs, postingToJob(posting))
+		}
+	})
+
+	doc.Find("[itemscope][itemtype]").Each(func(_ int, scope *goquery.Selection) {
+		itemType, _ := scope.Attr("itemtype")
+		if isPostingType(itemType) {
+			jobs = append(jobs, postingToJob(microdataItem(scope)))
+		}
+	})
+
+	return jobs
+}
+
+// findPostings collects the JobPosting objects anywhere in a JSON-LD value:
+// on their own, in an array, a @graph or the items of an ItemList.
+func findPostings(data any) []map[string]any {
+	var postings []map[string]any
+
+	switch value := data.(type) {
+	case []any:
+		for _, item := range value {
+			postings = append(postings, findPostings(item)...)
+		}
+	case map[string]any:
+		if isPostingType(value["@type"]) {
+			return []map[string]any{value}
+		}
+		for _, key := range []string{"@graph", "itemListElement", "item"} {
+			if nested, ok := value[key]; ok {
+				postings = append(postings, findPostings(nested)...)
+			}
+		}
+	}
+
+	return postings
+}
+
+// isPostingType matches "JobPosting", "schema:JobPosting" and
+// "https://schema.org/JobPosting", alone or among several types.
+func isPostingType(t any) bool {
+	switch value := t.(type) {
+	case string:
+		for _, name := range strings.Fields(value) {
+			if strings.HasSuffix(name, "JobPosting") {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if isPostingType(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func postingToJob(posting map[string]any) domain.Job {
+	job := domain.Job{
+		ExternalID:  identifier(posting["identifier"]),
+		Title:       text(first(posting, "title", "name")),
+		Company:     text(posting["hiringOrganization"]),
+		City:        locationText(posting["jobLocation"]),
+		Link:        text(posting["url"]),
+		Description: plainText(text(posting["description"])),
+		Date:        text(posting["datePosted"]),
+	}
+
+	if job.ExternalID == "" {
+		// the url is as unique as an identifier within a site
+		job.ExternalID = job.Link
+	}
+	if posted, ok := parsePostingDate(job.Date); ok {
+		job.PostedAt = posted
+	}
+
+	employment := values(posting["employmentType"])
+	job.WorkType = strings.Join(employment, ", ")
+	for _, value := range employment {
+		if job.Employment = postingEmployment[strings.ToUpper(value)]; job.Employment != "" {
+			break
+		}
+	}
+
+	for _, value := range values(posting["jobLocationType"]) {
+		if strings.EqualFold(value, "TELECOMMUTE") {
+			job.WorkMode = domain.WorkModeRemote
+		}
+	}
+
+	job.Pay = postingSalary(posting["baseSalary"])
+	if job.Pay.Known() {
+		job.Salary = salaryText(job.Pay)
+	}
+
+	return job
+}
+
+var postingEmployment = map[string]domain.EmploymentType{
+	"FULL_TIME":  domain.EmploymentFullTime,
+	"PART_TIME":  domain.EmploymentPartTime,
+	"CONTRACTOR": domain.EmploymentContract,
+	"TEMPORARY":  domain.EmploymentContract,
+	"PER_DIEM":   domain.EmploymentContract,
+	"INTERN":     domain.EmploymentInternship,
+}
+
+// postingPeriods converts the unit of a salary into a period of
+// domain.Salary and the factor of the amounts. Days and weeks are turned
+// into months of 21 days and 4 weeks, the 168 hours domain.Salary assumes.
+var postingPeriods = map[string]struct {
+	period string
+	factor int64
+}{
+	"HOUR":  {domain.PeriodHour, 1},
+	"DAY":   {domain.PeriodMonth, 21},
+	"WEEK":  {domain.PeriodMonth, 4},
+	"MONTH": {domain.PeriodMonth, 1},
+	"YEAR":  {domain.PeriodYear, 1},
+}
+
+// postingSalary reads a MonetaryAmount, whose value is a number or a
+// QuantitativeValue with a value or a range.
+func postingSalary(data any) domain.Salary {
+	amount, ok := data.(map[string]any)
+	if !ok {
+		return domain.Salary{}
+	}
+
+	salary := domain.Salary{Currency: strings.ToUpper(text(amount["currency"]))}
+	unit := text(amount["unitText"])
+
+	switch value := amount["value"].(type) {
+	case map[string]any:
+		if exact, ok := number(value["value"]); ok {
+			salary.Min, salary.Max = exact, exact
+		} else {
+			salary.Min, _ = number(value["minValue"])
+			salary.Max, _ = number(value["maxValue"])
+		}
+		if unitText := text(value["unitText"]); unitText != "" {
+			unit = unitText
+		}
+	default:
+		if exact, ok := number(value); ok {
+			salary.Min, salary.Max = exact, exact
+		}
+	}
+
+	if period, ok := postingPeriods[strings.ToUpper(unit)]; ok {
+		salary.Period = period.period
+		salary.Min *= period.factor
+		salary.Max *= period.factor
+	}
+
+	return salary
+}
+
+// salaryText describes a salary stated only as structured data, the way the
+// cards show it.
+func salaryText(salary domain.Salary) string {
+	amount := strconv.FormatInt(salary.Min, 10)
+	switch {
+	case salary.Min == 0:
+		amount = strconv.FormatInt(salary.Max, 10)
+	case salary.Max > salary.Min:
+		amount = fmt.Sprintf("%d–%d", salary.Min, salary.Max)
+	}
+
+	text := strings.TrimSpace(amount + " " + salary.Currency)
+	if salary.Period != "" {
+		text += " / " + salary.Period
+	}
+	return text
+}
+
+var postingDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// parsePostingDate reads an ISO 8601 date, one without a zone is taken as
+// UTC.
+func parsePostingDate(raw string) (time.Time, bool) {
+	for _, layout := range postingDateLayouts {
+		if posted, err := time.Parse(layout, raw); err == nil {
+			return posted, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// locationText joins the locality, region and country of the first Place
+// of a posting, e.g. "Київ, Київська область, UA".
+func locationText(data any) string {
+	if list, ok := data.([]any); ok {
+		if len(list) == 0 {
+			return ""
+		}
+		data = list[0]
+	}
+
+	place, ok := data.(map[string]any)
+	if !ok {
+		return text(data)
+	}
+
+	address, ok := place["address"].(map[string]any)
+	if !ok {
+		return text(first(place, "address", "name"))
+	}
+
+	var parts []string
+	for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+		if part := text(address[key]); part != "" && !containsFold(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// microdataItem turns an itemscope into the shape of a JSON-LD object. The
+// properties of nested items belong to those items only.
+func microdataItem(scope *goquery.Selection) map[string]any {
+	item := make(map[string]any)
+	if itemType, ok := scope.Attr("itemtype"); ok {
+		item["@type"] = itemType
+	}
+
+	scope.Find("[itemprop]").Each(func(_ int, prop *goquery.Selection) {
+		owner := prop.ParentsFiltered("[itemscope]").First()
+		if owner.Length() == 0 || owner.Get(0) != scope.Get(0) {
+			return
+		}
+
+		var value any
+		if _, nested := prop.Attr("itemscope"); nested {
+			value = microdataItem(prop)
+		} else {
+			value = microdataValue(prop)
+		}
+
+		names, _ := prop.Attr("itemprop")
+		for _, name := range strings.Fields(names) {
+			switch existing := item[name].(type) {
+			case nil:
+				item[name] = value
+			case []any:
+				item[name] = append(existing, value)
+			default:
+				item[name] = []any{existing, value}
+			}
+		}
+	})
+
+	return item
+}
+
+func microdataValue(prop *goquery.Selection) string {
+	attrs := map[string]string{
+		"meta": "content",
+		"a":    "href",
+		"link": "href",
+		"img":  "src",
+		"time": "datetime",
+		"data": "value",
+	}
+	if attr, ok := attrs[goquery.NodeName(prop)]; ok {
+		if value, ok := prop.Attr(attr); ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	if value, ok := prop.Attr("content"); ok {
+		return strings.TrimSpace(value)
+	}
+	return cleanText(prop.Text())
+}
+
+// text reads a JSON-LD value as text: a string or number as it is, an
+// object by its name or value and a list by its first item.
+func text(data any) string {
+	switch value := data.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case map[string]any:
+		return text(first(value, "name", "value", "@value"))
+	case []any:
+		for _, item := range value {
+			if s := text(item); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// identifier reads a PropertyValue by its value, its name is the one of the
+// issuer.
+func identifier(data any) string {
+	if object, ok := data.(map[string]any); ok {
+		return text(first(object, "value", "@value"))
+	}
+	return text(data)
+}
+
+// values reads a JSON-LD value that may be a list as texts.
+func values(data any) []string {
+	list, ok := data.([]any)
+	if !ok {
+		list = []any{data}
+	}
+
+	var result []string
+	for _, item := range list {
+		if s := text(item); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func number(data any) (int64, bool) {
+	switch value := data.(type) {
+	case float64:
+		return int64(math.Round(value)), value > 0
+	case string:
+		parsed, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), " ", ""), 64)
+		return int64(math.Round(parsed)), err == nil && parsed > 0
+	}
+	return 0, false
+}
+
+func first(object map[string]any, keys ...string) any {
+	for _, key := range keys {
+		if value, ok := object[key]; ok && value != nil && value != "" {
+			return value
+		}
+	}
+	return nil
+}
+
+// plainText drops the markup postings often keep, escaped or not, in their
+// description.
+func plainText(s string) string {
+	s = html.UnescapeString(s)
+	if !strings.Contains(s, "<") {
+		return cleanText(s)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(s))
+	if err != nil {
+		return cleanText(s)
+	}
+	return cleanText(doc.Text())
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
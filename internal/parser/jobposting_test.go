@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/parser/setters"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func readPostings(t *testing.T, name string) []domain.Job {
+	t.Helper()
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(readFixture(t, name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return JobPostings(doc)
+}
+
+func TestJobPostingsJSONLD(t *testing.T) {
+	postings := readPostings(t, "jsonld.html")
+	if len(postings) != 2 {
+		t.Fatalf("got %d postings, want 2", len(postings))
+	}
+
+	goDev := postings[0]
+	if goDev.ExternalID != "p-1" || goDev.Title != "Go Developer" || goDev.Company != "Acme" {
+		t.Errorf("first posting = %q %q at %q", goDev.ExternalID, goDev.Title, goDev.Company)
+	}
+	if goDev.City != "Київ, Київська область, UA" {
+		t.Errorf("first posting city = %q", goDev.City)
+	}
+	if goDev.Description != "Build APIs in Go" {
+		t.Errorf("first posting description = %q, want the markup dropped", goDev.Description)
+	}
+	if want := time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC); !goDev.PostedAt.Equal(want) {
+		t.Errorf("first posting posted at %v, want %v", goDev.PostedAt, want)
+	}
+	if goDev.Employment != domain.EmploymentFullTime || goDev.WorkMode != domain.WorkModeRemote {
+		t.Errorf("first posting employment and work mode = %q, %q", goDev.Employment, goDev.WorkMode)
+	}
+	wantPay := domain.Salary{Min: 3000, Max: 4500, Currency: "USD", Period: domain.PeriodMonth}
+	if goDev.Pay != wantPay || goDev.Salary != "3000–4500 USD / month" {
+		t.Errorf("first posting pay = %+v %q, want %+v", goDev.Pay, goDev.Salary, wantPay)
+	}
+
+	rustDev := postings[1]
+	if rustDev.ExternalID != "https://ua.jooble.org/desc/8822" {
+		t.Errorf("second posting id = %q, want its url without an identifier", rustDev.ExternalID)
+	}
+	if rustDev.Company != "Initech" || rustDev.City != "Львів" || rustDev.Employment != domain.EmploymentPartTime {
+		t.Errorf("second posting = %q in %q, %q", rustDev.Company, rustDev.City, rustDev.Employment)
+	}
+	if want := time.Date(2025, time.October, 21, 6, 30, 0, 0, time.UTC); !rustDev.PostedAt.Equal(want) {
+		t.Errorf("second posting posted at %v, want %v", rustDev.PostedAt, want)
+	}
+	if want := (domain.Salary{Min: 250, Max: 250, Currency: "UAH", Period: domain.PeriodHour}); rustDev.Pay != want {
+		t.Errorf("second posting pay = %+v, want %+v", rustDev.Pay, want)
+	}
+}
+
+func TestJobPostingsMicrodata(t *testing.T) {
+	postings := readPostings(t, "microdata.html")
+	if len(postings) != 1 {
+		t.Fatalf("got %d postings, want 1", len(postings))
+	}
+
+	qa := postings[0]
+	if qa.Title != "QA Engineer" || qa.Company != "Acme" || qa.City != "Одеса, UA" {
+		t.Errorf("posting = %q at %q in %q", qa.Title, qa.Company, qa.City)
+	}
+	if qa.Link != "https://jobs.dou.ua/companies/acme/vacancies/321654/" {
+		t.Errorf("posting link = %q", qa.Link)
+	}
+	if qa.Description != "Manual and automated testing" || qa.Employment != domain.EmploymentInternship {
+		t.Errorf("posting description and employment = %q, %q", qa.Description, qa.Employment)
+	}
+}
+
+func TestJobPostingsNone(t *testing.T) {
+	if postings := readPostings(t, "jooble_cards.html"); len(postings) != 0 {
+		t.Errorf("got %d postings from a page without any", len(postings))
+	}
+}
+
+func TestParseFallsBackToPostings(t *testing.T) {
+	p := newParser(t, &setters.DefaultSpec, &config.HealthConfig{})
+
+	result, err := p.Parse(readFixture(t, "jsonld.html"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// ids are read from the links like the ones of the cards, not from the
+	// identifier of the posting
+	ids := make([]string, len(result.Jobs))
+	for i, job := range result.Jobs {
+		ids[i] = job.ExternalID
+	}
+	if want := []string{"-7311", "8822"}; !slices.Equal(ids, want) {
+		t.Errorf("Parse() ids = %v, want %v", ids, want)
+	}
+	if result.Health.Cards != 2 || result.Health.Degraded {
+		t.Errorf("Health = %s, want 2 healthy postings", result.Health)
+	}
+}
+
+func TestParsePostingsWithoutLinkID(t *testing.T) {
+	spec := setters.DefaultSpec
+	spec.LinkID = ""
+	p := newParser(t, &spec, &config.HealthConfig{})
+
+	result, err := p.Parse(readFixture(t, "jsonld.html"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// the card id attribute can't be read from a link
+	if len(result.Jobs) != 0 || result.Rejected() != 2 {
+		t.Errorf("got %d jobs and %d rejected, want every posting rejected", len(result.Jobs), result.Rejected())
+	}
+}
+
+func TestParsePostingsWithLinkField(t *testing.T) {
+	spec := &setters.Spec{
+		Card: "li.l-vacancy",
+		Fields: []setters.FieldSpec{
+			{Field: setters.FieldID, Selector: "a.vt", Attr: "href", Transforms: []setters.Transform{
+				{Kind: setters.TransformRegex, Pattern: `/vacancies/(\d+)`},
+			}},
+			{Field: "title", Selector: "a.vt", Required: true},
+		},
+	}
+	p := newParser(t, spec, &config.HealthConfig{})
+
+	result, err := p.Parse(readFixture(t, "microdata.html"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(result.Jobs) != 1 || result.Jobs[0].ExternalID != "321654" {
+		t.Errorf("Parse() = %+v, want the vacancy 321654", result.Jobs)
+	}
+}
+
+func TestHasPostings(t *testing.T) {
+	p := newParser(t, &setters.DefaultSpec, &config.HealthConfig{})
+
+	if !p.HasPostings(readFixture(t, "jsonld.html")) {
+		t.Errorf("HasPostings() = false for a page of postings")
+	}
+	if p.HasPostings(readFixture(t, "jooble_cards.html")) {
+		t.Errorf("HasPostings() = true for a page of cards")
+	}
+
+	// the link of the microdata posting has no jooble id
+	if p.HasPostings(readFixture(t, "microdata.html")) {
+		t.Errorf("HasPostings() = true for postings without an id")
+	}
+}
@@ -1,9 +1,13 @@
 package parser
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"jooble-parser/internal/config"
 	"jooble-parser/internal/domain"
 	"jooble-parser/internal/parser/setters"
+	"slices"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -11,48 +15,176 @@ import (
 )
 
 type JobParser struct {
-	propsSetter []setters.PropSeter
+	list        string
+	card        string
+	propsSetter []setters.FieldSetter
+	linkID      func(link string) string
+	health      *healthCheck
 	logger      *zap.Logger
 }
 
-func NewJobParser(logger *zap.Logger, props ...setters.PropSeter) *JobParser {
-	if len(props) == 0 {
-		panic("No job props setters set")
+func NewJobParser(logger *zap.Logger, spec *setters.Spec, health *config.HealthConfig) (*JobParser, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid selector spec: %w", err)
+	}
+
+	props, err := spec.Setters()
+	if err != nil {
+		return nil, err
+	}
+
+	linkID, err := spec.LinkIDReader()
+	if err != nil {
+		return nil, err
 	}
 
 	return &JobParser{
+		list:        spec.List,
+		card:        spec.Card,
 		propsSetter: props,
+		linkID:      linkID,
+		health:      newHealthCheck(spec, health),
 		logger:      logger,
-	}
+	}, nil
 }
 
-func (p *JobParser) Parse(html string) ([]domain.Job, error) {
+// Parse scrapes the job cards of html and reports how well the selectors
+// matched them. A card is rejected when a required field fails and kept
+// without the field when an optional one does, the failures are listed in
+// the result. When no card gives a job, the JobPosting data the page embeds
+// is read instead. The jobs of a degraded page are returned as well, it is
+// up to the caller to refuse them. An error means the page couldn't be read
+// at all.
+func (p *JobParser) Parse(html string) (*ParseResult, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	var jobs []domain.Job
-	var parseErrors []error
+	result := &ParseResult{}
+	var parsed []domain.Job // every card, the health covers the rejected ones too
+	seen := make(map[string]struct{})
 
-	doc.Find("ul.kiBEcn").
-		Find(`div[data-test-name="_jobCard"]`).
+	cards := p.cards(doc)
+	cards.
 		Each(func(i int, s *goquery.Selection) {
 			job := &domain.Job{}
+			var cardErrors []CardError
+			rejected := false
 
-			for _, propSeter := range p.propsSetter {
-				if err := propSeter(job, s); err != nil {
-					parseErrors = append(parseErrors, fmt.Errorf("job %d: %w", i, err))
-					return
+			for _, setter := range p.propsSetter {
+				if err := setter.Set(job, s); err != nil {
+					cardErrors = append(cardErrors, CardError{Card: i, Field: setter.Field, Required: setter.Required, Err: err})
+					rejected = rejected || setter.Required
 				}
 			}
 
-			jobs = append(jobs, *job)
+			for _, cardErr := range cardErrors {
+				cardErr.ID = job.ExternalID
+				result.Errors = append(result.Errors, cardErr)
+			}
+			parsed = append(parsed, *job)
+			if rejected {
+				return
+			}
+
+			// paginated results may repeat a card on several pages
+			if _, ok := seen[job.ExternalID]; ok {
+				return
+			}
+			seen[job.ExternalID] = struct{}{}
+
+			result.Jobs = append(result.Jobs, *job)
 		})
 
-	if len(parseErrors) > 0 {
-		return jobs, fmt.Errorf("parsing errors: %v", parseErrors)
+	if len(result.Jobs) == 0 {
+		if postings := p.postings(doc, result); len(postings) > 0 {
+			p.logger.Info("No job cards read, using the structured job data of the page",
+				zap.Int("cards", cards.Length()), zap.Int("postings", len(postings)))
+			result.Health = p.health.report(len(postings), postings)
+			return result, nil
+		}
+	}
+
+	result.Health = p.health.report(cards.Length(), parsed)
+	return result, nil
+}
+
+// postings adds the embedded JobPostings of doc to result. Their id is read
+// from their link the way the spec reads it from cards, so a job keeps its id
+// whichever of the two it was found in. Like cards a posting without an id is
+// rejected. It returns all postings found.
+func (p *JobParser) postings(doc *goquery.Document, result *ParseResult) []domain.Job {
+	postings := JobPostings(doc)
+	seen := make(map[string]struct{})
+
+	for i := range postings {
+		postings[i].ExternalID = ""
+		if p.linkID != nil {
+			postings[i].ExternalID = p.linkID(postings[i].Link)
+		}
+	}
+
+	for i, job := range postings {
+		if job.ExternalID == "" {
+			result.Errors = append(result.Errors, CardError{Card: i, Field: setters.FieldID, Required: true, Err: setters.ErrMissing})
+			continue
+		}
+		if _, ok := seen[job.ExternalID]; ok {
+			continue
+		}
+		seen[job.ExternalID] = struct{}{}
+		result.Jobs = append(result.Jobs, job)
 	}
 
-	return jobs, nil
+	return postings
+}
+
+// HasPostings reports whether html embeds a JobPosting the parser can read an
+// id of, a mention of the type alone is not enough.
+func (p *JobParser) HasPostings(html string) bool {
+	if p.linkID == nil || !strings.Contains(html, "JobPosting") {
+		return false
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(JobPostings(doc), func(job domain.Job) bool {
+		return p.linkID(job.Link) != ""
+	})
+}
+
+// Fingerprint hashes the job cards of html, ignoring markup, attributes other
+// than the card id and whitespace, so a re-rendered page with the same
+// listings keeps its fingerprint. An empty string means there are no cards.
+func (p *JobParser) Fingerprint(html string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	found := p.cards(doc)
+	if found.Length() == 0 {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	found.Each(func(_ int, s *goquery.Selection) {
+		id, _ := s.Attr("id")
+		hash.Write([]byte(id))
+		hash.Write([]byte{0})
+		hash.Write([]byte(strings.Join(strings.Fields(s.Text()), " ")))
+		hash.Write([]byte{0})
+	})
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (p *JobParser) cards(doc *goquery.Document) *goquery.Selection {
+	if p.list == "" {
+		return doc.Find(p.card)
+	}
+	return doc.Find(p.list).Find(p.card)
 }
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"errors"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/parser/setters"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func newParser(t *testing.T, spec *setters.Spec, health *config.HealthConfig) *JobParser {
+	t.Helper()
+
+	p, err := NewJobParser(zap.NewNop(), spec, health)
+	if err != nil {
+		t.Fatalf("NewJobParser() error = %v", err)
+	}
+	return p
+}
+
+func TestParseCards(t *testing.T) {
+	p := newParser(t, &setters.DefaultSpec, &config.HealthConfig{})
+
+	result, err := p.Parse(readFixture(t, "jooble_cards.html"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	ids := make([]string, len(result.Jobs))
+	for i, job := range result.Jobs {
+		ids[i] = job.ExternalID
+	}
+	if want := []string{"464575341174131141", "-4134916308710032189"}; !slices.Equal(ids, want) {
+		t.Fatalf("Parse() ids = %v, want %v", ids, want)
+	}
+
+	job := result.Jobs[0]
+	if job.Title != "Senior Golang Developer" || job.Company != "Empat" || job.City != "Київ" {
+		t.Errorf("first job = %q at %q in %q", job.Title, job.Company, job.City)
+	}
+	if job.Link != "https://ua.jooble.org/desc/464575341174131141?ckey=golang+developer" {
+		t.Errorf("first job link = %q", job.Link)
+	}
+	if job.Salary != "від 90 000 грн" || job.WorkType != "Повна зайнятість" || job.Date != "2 дні тому" {
+		t.Errorf("first job salary, work type and date = %q, %q, %q", job.Salary, job.WorkType, job.Date)
+	}
+	if want := []string{"Віддалена робота", "Гнучкий графік"}; !slices.Equal(job.Tags, want) {
+		t.Errorf("first job tags = %v, want %v", job.Tags, want)
+	}
+	if company := result.Jobs[1].Company; company != "" {
+		t.Errorf("second job company = %q, want it left empty", company)
+	}
+
+	if rejected := result.Rejected(); rejected != 1 {
+		t.Errorf("Rejected() = %d, want 1", rejected)
+	}
+	for _, cardErr := range result.Errors {
+		if cardErr.Card != 2 || cardErr.ID != "6097966535073320088" || !cardErr.Required || !errors.Is(cardErr, setters.ErrMissing) {
+			t.Errorf("unexpected card error %v", cardErr)
+		}
+	}
+
+	if result.Health.Cards != 4 || result.Health.Degraded {
+		t.Errorf("Health = %s, degraded %v, want 4 healthy cards", result.Health, result.Health.Degraded)
+	}
+}
+
+func TestParseDegraded(t *testing.T) {
+	minCards, minFillRate := 1, 0.9
+	p := newParser(t, &setters.DefaultSpec, &config.HealthConfig{MinCards: &minCards, MinFillRate: &minFillRate})
+
+	result, err := p.Parse(readFixture(t, "jooble_cards.html"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	// one card in four has no title
+	if !result.Health.Degraded || !slices.Contains(result.Health.Missing, "title") {
+		t.Errorf("Health = %s, want degraded by the title", result.Health)
+	}
+	if len(result.Jobs) != 2 {
+		t.Errorf("got %d jobs, want the jobs of a degraded page returned as well", len(result.Jobs))
+	}
+}
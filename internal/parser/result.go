@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"fmt"
+	"jooble-parser/internal/domain"
+)
+
+// ParseResult holds the jobs scraped from a page and what went wrong on the
+// cards that were rejected or only partially read.
+type ParseResult struct {
+	Jobs   []domain.Job
+	Errors []CardError
+	Health *Health
+}
+
+// CardError is the failure of the setter of one field on one card.
+type CardError struct {
+	Card     int    // position of the card on the page
+	ID       string // external id of the job, empty when it couldn't be read
+	Field    string
+	Required bool // the card was rejected
+	Err      error
+}
+
+func (e CardError) Error() string {
+	return fmt.Sprintf("card %d (%q) %s: %v", e.Card, e.ID, e.Field, e.Err)
+}
+
+func (e CardError) Unwrap() error {
+	return e.Err
+}
+
+// Rejected counts the cards dropped for a failed required field.
+func (r *ParseResult) Rejected() int {
+	rejected := make(map[int]struct{})
+	for _, cardErr := range r.Errors {
+		if cardErr.Required {
+			rejected[cardErr.Card] = struct{}{}
+		}
+	}
+	return len(rejected)
+}
@@ -1,79 +1,74 @@
 package setters
 
 import (
+	"errors"
 	"jooble-parser/internal/domain"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
-var AllSetters = []PropSeter{
-	IdSeter,
-	LinkSeter,
-	CompanySeter,
-	CitySeter,
-	SalarySeter,
-	WorkTypeSeter,
-	DateSeter,
-	DescriptionSeter,
-	TagsSeter,
-}
-
 type PropSeter func(job *domain.Job, selection *goquery.Selection) error
 
-func IdSeter(job *domain.Job, selection *goquery.Selection) error {
-	if id, exists := selection.Attr("id"); exists {
-		job.ExternalID = id
-	}
-	return nil
-}
-
-func LinkSeter(job *domain.Job, selection *goquery.Selection) error {
-	linkSel := selection.Find("h2 a")
-	job.Title = strings.TrimSpace(linkSel.Text())
-	if href, ok := linkSel.Attr("href"); ok {
-		job.Link = strings.TrimSpace(href)
-	}
-
-	return nil
+// FieldSetter sets one field of the spec. A card where a required setter
+// fails is rejected, the failure of an optional one only leaves its field
+// empty.
+type FieldSetter struct {
+	Field    string
+	Required bool
+	Set      PropSeter
 }
 
-func DescriptionSeter(job *domain.Job, selection *goquery.Selection) error {
-	job.Description = strings.TrimSpace(selection.Find("div.GEyos4.e9eiOZ").Text())
-	return nil
-}
-
-func CompanySeter(job *domain.Job, selection *goquery.Selection) error {
-	job.Company = strings.TrimSpace(selection.Find(`p[data-test-name="_companyName"]`).Text())
-	return nil
-}
+// ErrMissing is returned by the setter of a required field that has no value
+// on the card.
+var ErrMissing = errors.New("value missing")
 
-func CitySeter(job *domain.Job, selection *goquery.Selection) error {
-	job.City = strings.TrimSpace(selection.Find("div.caption.NTRJBV").Text())
-	return nil
-}
+// FieldID is the field holding the external id of a job.
+const FieldID = "id"
 
-func SalarySeter(job *domain.Job, selection *goquery.Selection) error {
-	job.Salary = strings.TrimSpace(selection.Find("p.b97WnG").Text())
-	return nil
+// fieldSetters store the values extracted for a field of the spec in a job.
+// Single valued fields receive the values joined with a space.
+var fieldSetters = map[string]func(job *domain.Job, values []string){
+	FieldID:       func(job *domain.Job, values []string) { job.ExternalID = joinValues(values) },
+	"title":       func(job *domain.Job, values []string) { job.Title = joinValues(values) },
+	"link":        func(job *domain.Job, values []string) { job.Link = joinValues(values) },
+	"company":     func(job *domain.Job, values []string) { job.Company = joinValues(values) },
+	"city":        func(job *domain.Job, values []string) { job.City = joinValues(values) },
+	"salary":      func(job *domain.Job, values []string) { job.Salary = joinValues(values) },
+	"work_type":   func(job *domain.Job, values []string) { job.WorkType = joinValues(values) },
+	"date":        func(job *domain.Job, values []string) { job.Date = joinValues(values) },
+	"description": func(job *domain.Job, values []string) { job.Description = joinValues(values) },
+	"tags":        func(job *domain.Job, values []string) { job.Tags = append(job.Tags, values...) },
 }
 
-func WorkTypeSeter(job *domain.Job, selection *goquery.Selection) error {
-	job.WorkType = strings.TrimSpace(selection.Find("p._1dYE+p").Text())
-	return nil
+func joinValues(values []string) string {
+	return strings.Join(values, " ")
 }
 
-func DateSeter(job *domain.Job, selection *goquery.Selection) error {
-	job.Date = strings.TrimSpace(selection.Find("div.GEyos4.e9eiOZ span:first-child").Text())
-	return nil
-}
-
-func TagsSeter(job *domain.Job, selection *goquery.Selection) error {
-	selection.Find("div.K8ZLnh.tag").Each(func(_ int, tagSel *goquery.Selection) {
-		tag := strings.TrimSpace(tagSel.Text())
-		if tag != "" {
-			job.Tags = append(job.Tags, tag)
-		}
-	})
-	return nil
+// Filled reports whether field of the spec has a value in job.
+func Filled(job *domain.Job, field string) bool {
+	switch field {
+	case FieldID:
+		return job.ExternalID != ""
+	case "title":
+		return job.Title != ""
+	case "link":
+		return job.Link != ""
+	case "company":
+		return job.Company != ""
+	case "city":
+		return job.City != ""
+	case "salary":
+		return job.Salary != ""
+	case "work_type":
+		return job.WorkType != ""
+	case "date":
+		return job.Date != ""
+	case "description":
+		return job.Description != ""
+	case "tags":
+		return len(job.Tags) > 0
+	default:
+		return false
+	}
 }
@@ -0,0 +1,341 @@
+package setters
+
+import (
+	"encoding/json"
+	"fmt"
+	"jooble-parser/internal/domain"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec describes where the job cards are on a search page and how every
+// field of a job is read from its card.
+type Spec struct {
+	List   string      `yaml:"list" json:"list"` // optional, cards are searched in the whole page without it
+	Card   string      `yaml:"card" json:"card"`
+	Fields []FieldSpec `yaml:"fields" json:"fields"`
+	LinkID string      `yaml:"link_id" json:"link_id"` // pattern of the id in a job link, needed when the id isn't read from a link
+}
+
+type FieldSpec struct {
+	Field      string      `yaml:"field" json:"field"`
+	Selector   string      `yaml:"selector" json:"selector"` // relative to the card, the card itself when empty
+	Attr       string      `yaml:"attr" json:"attr"`         // the attribute is read instead of the text
+	Multiple   bool        `yaml:"multiple" json:"multiple"` // every match is a value instead of the text of all matches
+	Required   bool        `yaml:"required" json:"required"` // a card without it is rejected, a page where it is mostly empty is degraded
+	Transforms []Transform `yaml:"transforms" json:"transforms"`
+}
+
+// Transform is applied to every value of a field in the order of the spec.
+//
+//	trim     removes surrounding whitespace
+//	regex    keeps the first group, or the whole match, of pattern and drops values that don't match
+//	replace  replaces the matches of pattern with replace
+//	join     joins all values into one with separator
+type Transform struct {
+	Kind      string `yaml:"kind" json:"kind"`
+	Pattern   string `yaml:"pattern" json:"pattern"`
+	Replace   string `yaml:"replace" json:"replace"`
+	Separator string `yaml:"separator" json:"separator"`
+}
+
+const (
+	TransformTrim    = "trim"
+	TransformRegex   = "regex"
+	TransformReplace = "replace"
+	TransformJoin    = "join"
+)
+
+var trim = []Transform{{Kind: TransformTrim}}
+
+// DefaultSpec matches the search page of jooble.org.
+var DefaultSpec = Spec{
+	List: "ul.kiBEcn",
+	Card: `div[data-test-name="_jobCard"]`,
+	Fields: []FieldSpec{
+		{Field: "id", Attr: "id", Required: true},
+		{Field: "title", Selector: "h2 a", Transforms: trim, Required: true},
+		{Field: "link", Selector: "h2 a", Attr: "href", Transforms: trim, Required: true},
+		{Field: "company", Selector: `p[data-test-name="_companyName"]`, Transforms: trim},
+		{Field: "city", Selector: "div.caption.NTRJBV", Transforms: trim},
+		{Field: "salary", Selector: "p.b97WnG", Transforms: trim},
+		{Field: "work_type", Selector: "p._1dYE+p", Transforms: trim},
+		{Field: "date", Selector: "div.GEyos4.e9eiOZ span:first-child", Transforms: trim},
+		{Field: "description", Selector: "div.GEyos4.e9eiOZ", Transforms: trim},
+		{Field: "tags", Selector: "div.K8ZLnh.tag", Multiple: true, Transforms: trim},
+	},
+	LinkID: `/desc/(-?\d+)`,
+}
+
+// LoadSpec reads a spec from a JSON file, or a YAML file for any other
+// extension, and validates it.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read selector spec: %w", err)
+	}
+
+	var spec Spec
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &spec)
+	} else {
+		err = yaml.Unmarshal(data, &spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse selector spec %s: %w", path, err)
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid selector spec %s: %w", path, err)
+	}
+
+	return &spec, nil
+}
+
+func (s *Spec) Validate() error {
+	if s.Card == "" {
+		return fmt.Errorf("card selector is required")
+	}
+	if err := validateSelector(s.Card); err != nil {
+		return fmt.Errorf("card: %w", err)
+	}
+	if s.List != "" {
+		if err := validateSelector(s.List); err != nil {
+			return fmt.Errorf("list: %w", err)
+		}
+	}
+	if len(s.Fields) == 0 {
+		return fmt.Errorf("at least one field is required")
+	}
+	if !slices.ContainsFunc(s.Fields, func(field FieldSpec) bool { return field.Field == FieldID }) {
+		return fmt.Errorf("the %s field is required, jobs are told apart by it", FieldID)
+	}
+
+	if s.LinkID != "" {
+		if _, err := (Transform{Kind: TransformRegex, Pattern: s.LinkID}).compile(); err != nil {
+			return fmt.Errorf("link_id: %w", err)
+		}
+	}
+
+	for i, field := range s.Fields {
+		if _, ok := fieldSetters[field.Field]; !ok {
+			return fmt.Errorf("fields[%d]: unknown field %q, known fields are %v", i, field.Field, knownFields())
+		}
+		if field.Selector != "" {
+			if err := validateSelector(field.Selector); err != nil {
+				return fmt.Errorf("fields[%d] %s: %w", i, field.Field, err)
+			}
+		}
+		for j, transform := range field.Transforms {
+			if _, err := transform.compile(); err != nil {
+				return fmt.Errorf("fields[%d] %s: transforms[%d]: %w", i, field.Field, j, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Setters builds a FieldSetter for every field of a validated spec.
+func (s *Spec) Setters() ([]FieldSetter, error) {
+	props := make([]FieldSetter, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		prop, err := field.setter()
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Field, err)
+		}
+		props = append(props, FieldSetter{Field: field.Field, Required: field.IsRequired(), Set: prop})
+	}
+	return props, nil
+}
+
+// LinkIDReader returns how the id a card would have is read from the link of
+// a job found without a card: by the link_id pattern, or by the transforms of
+// the id field when it is read from a link. It returns nil when the spec
+// doesn't say.
+func (s *Spec) LinkIDReader() (func(link string) string, error) {
+	var transforms []Transform
+	if s.LinkID != "" {
+		transforms = []Transform{{Kind: TransformRegex, Pattern: s.LinkID}}
+	} else {
+		i := slices.IndexFunc(s.Fields, func(field FieldSpec) bool { return field.Field == FieldID })
+		if i < 0 || s.Fields[i].Attr != "href" {
+			return nil, nil
+		}
+		transforms = s.Fields[i].Transforms
+	}
+
+	compiled := make([]func([]string) []string, 0, len(transforms))
+	for _, transform := range transforms {
+		c, err := transform.compile()
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, c)
+	}
+
+	return func(link string) string {
+		values := []string{link}
+		for _, transform := range compiled {
+			values = transform(values)
+		}
+		return joinValues(slices.DeleteFunc(values, func(value string) bool { return value == "" }))
+	}, nil
+}
+
+// IsRequired reports whether a card without the field is rejected. The id
+// always is, a job without one would be stored with an empty id and could
+// never be told apart from the others.
+func (f FieldSpec) IsRequired() bool {
+	return f.Required || f.Field == FieldID
+}
+
+func (f FieldSpec) setter() (PropSeter, error) {
+	set, ok := fieldSetters[f.Field]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", f.Field)
+	}
+
+	transforms := make([]func([]string) []string, 0, len(f.Transforms))
+	for _, transform := range f.Transforms {
+		compiled, err := transform.compile()
+		if err != nil {
+			return nil, err
+		}
+		transforms = append(transforms, compiled)
+	}
+
+	return func(job *domain.Job, selection *goquery.Selection) error {
+		values := f.extract(selection)
+		for _, transform := range transforms {
+			values = transform(values)
+		}
+
+		values = slices.DeleteFunc(values, func(value string) bool { return value == "" })
+		if len(values) == 0 && f.IsRequired() {
+			return ErrMissing
+		}
+		set(job, values)
+		return nil
+	}, nil
+}
+
+func (f FieldSpec) extract(card *goquery.Selection) []string {
+	selection := card
+	if f.Selector != "" {
+		selection = card.Find(f.Selector)
+	}
+
+	read := func(s *goquery.Selection) string {
+		if f.Attr == "" {
+			return s.Text()
+		}
+		value, _ := s.Attr(f.Attr)
+		return value
+	}
+
+	if !f.Multiple {
+		if selection.Length() == 0 {
+			return nil
+		}
+		return []string{read(selection)}
+	}
+
+	var values []string
+	selection.Each(func(_ int, s *goquery.Selection) {
+		values = append(values, read(s))
+	})
+	return values
+}
+
+func (t Transform) compile() (func([]string) []string, error) {
+	switch t.Kind {
+	case TransformTrim:
+		return func(values []string) []string {
+			for i, value := range values {
+				values[i] = strings.TrimSpace(value)
+			}
+			return values
+		}, nil
+
+	case TransformRegex:
+		pattern, err := compilePattern(t)
+		if err != nil {
+			return nil, err
+		}
+		return func(values []string) []string {
+			var matched []string
+			for _, value := range values {
+				match := pattern.FindStringSubmatch(value)
+				switch {
+				case match == nil:
+				case len(match) > 1:
+					matched = append(matched, match[1])
+				default:
+					matched = append(matched, match[0])
+				}
+			}
+			return matched
+		}, nil
+
+	case TransformReplace:
+		pattern, err := compilePattern(t)
+		if err != nil {
+			return nil, err
+		}
+		return func(values []string) []string {
+			for i, value := range values {
+				values[i] = pattern.ReplaceAllString(value, t.Replace)
+			}
+			return values
+		}, nil
+
+	case TransformJoin:
+		return func(values []string) []string {
+			if len(values) == 0 {
+				return values
+			}
+			return []string{strings.Join(values, t.Separator)}
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown transform %q, known transforms are %q, %q, %q and %q",
+			t.Kind, TransformTrim, TransformRegex, TransformReplace, TransformJoin)
+	}
+}
+
+func compilePattern(t Transform) (*regexp.Regexp, error) {
+	if t.Pattern == "" {
+		return nil, fmt.Errorf("%s transform requires a pattern", t.Kind)
+	}
+	pattern, err := regexp.Compile(t.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s pattern: %w", t.Kind, err)
+	}
+	return pattern, nil
+}
+
+// validateSelector reports a selector that does not compile, goquery would
+// silently match nothing with it.
+func validateSelector(selector string) error {
+	if _, err := cascadia.Compile(selector); err != nil {
+		return fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+	return nil
+}
+
+func knownFields() []string {
+	fields := make([]string, 0, len(fieldSetters))
+	for field := range fieldSetters {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
@@ -0,0 +1,77 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// FingerprintsRepository keeps the fingerprint of the last parsed result page
+// of every search url.
+type FingerprintsRepository interface {
+	GetFingerprint(url string) (string, error)
+	SaveFingerprint(url string, hash string) error
+	TouchFingerprint(url string) error
+
+	InitSchema() error
+}
+
+type SQLiteFingerprintsRepository struct {
+	db *sql.DB
+}
+
+func NewSQLiteFingerprintsRepository(db *sql.DB) *SQLiteFingerprintsRepository {
+	return &SQLiteFingerprintsRepository{db: db}
+}
+
+func (r *SQLiteFingerprintsRepository) InitSchema() error {
+	query := `
+    CREATE TABLE IF NOT EXISTS fingerprints (
+        url TEXT PRIMARY KEY,
+        hash TEXT NOT NULL,
+        checked_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+    );
+    `
+
+	_, err := r.db.Exec(query)
+	return err
+}
+
+// GetFingerprint returns an empty hash for a url seen for the first time.
+func (r *SQLiteFingerprintsRepository) GetFingerprint(url string) (string, error) {
+	var hash string
+	query := `SELECT hash FROM fingerprints WHERE url = ?`
+	err := r.db.QueryRow(query, url).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get fingerprint: %w", err)
+	}
+	return hash, nil
+}
+
+func (r *SQLiteFingerprintsRepository) SaveFingerprint(url string, hash string) error {
+	query := `
+    INSERT INTO fingerprints (url, hash) VALUES (?, ?)
+    ON CONFLICT(url) DO UPDATE SET
+        hash = excluded.hash,
+        checked_at = CURRENT_TIMESTAMP,
+        updated_at = CURRENT_TIMESTAMP
+    `
+
+	if _, err := r.db.Exec(query, url, hash); err != nil {
+		return fmt.Errorf("failed to save fingerprint: %w", err)
+	}
+	return nil
+}
+
+// TouchFingerprint records that the page of url was checked and found unchanged.
+func (r *SQLiteFingerprintsRepository) TouchFingerprint(url string) error {
+	query := `UPDATE fingerprints SET checked_at = CURRENT_TIMESTAMP WHERE url = ?`
+	if _, err := r.db.Exec(query, url); err != nil {
+		return fmt.Errorf("failed to touch fingerprint: %w", err)
+	}
+	return nil
+}
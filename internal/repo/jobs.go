@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"jooble-parser/internal/domain"
 	"strings"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -16,9 +17,10 @@ type JobsRepository interface {
 	UpdateJob(job domain.Job) error
 	DeleteJob(id int64) error
 
-	GetByExternalID(externalID string) (*domain.Job, error)
-	JobExists(externalID string) (bool, error)
+	GetByExternalID(source string, externalID string) (*domain.Job, error)
+	JobExists(source string, externalID string) (bool, error)
 	Count() (int64, error)
+	CountByCity() (map[string]int64, error)
 
 	InitSchema() error
 }
@@ -32,11 +34,11 @@ func NewSQLiteJobsRepository(db *sql.DB) *SQLiteJobsRepository {
 	return &SQLiteJobsRepository{db: db}
 }
 
-func (r *SQLiteJobsRepository) InitSchema() error {
-	query := `
-    CREATE TABLE IF NOT EXISTS jobs (
+// jobsTable defines the jobs table, it follows CREATE TABLE and its name.
+const jobsTable = ` (
         id INTEGER PRIMARY KEY AUTOINCREMENT,
-        external_id TEXT UNIQUE,
+        source TEXT NOT NULL DEFAULT '',
+        external_id TEXT,
         title TEXT NOT NULL,
         company TEXT,
         city TEXT,
@@ -45,9 +47,31 @@ func (r *SQLiteJobsRepository) InitSchema() error {
         description TEXT,
         work_type TEXT,
         date TEXT,
+        full_description TEXT NOT NULL DEFAULT '',
+        requirements TEXT NOT NULL DEFAULT '',
+        employer_info TEXT NOT NULL DEFAULT '',
+        original_url TEXT NOT NULL DEFAULT '',
+        salary_min INTEGER NOT NULL DEFAULT 0,
+        salary_max INTEGER NOT NULL DEFAULT 0,
+        salary_currency TEXT NOT NULL DEFAULT '',
+        salary_period TEXT NOT NULL DEFAULT '',
+        salary_basis TEXT NOT NULL DEFAULT '',
+        posted_at DATETIME,
+        employment TEXT NOT NULL DEFAULT '',
+        work_mode TEXT NOT NULL DEFAULT '',
+        location_city TEXT NOT NULL DEFAULT '',
+        location_region TEXT NOT NULL DEFAULT '',
+        location_country TEXT NOT NULL DEFAULT '',
+        remote INTEGER NOT NULL DEFAULT 0,
+        relocation INTEGER NOT NULL DEFAULT 0,
         created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-        updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-    );
+        updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+        UNIQUE(source, external_id)
+    )`
+
+func (r *SQLiteJobsRepository) InitSchema() error {
+	query := `
+    CREATE TABLE IF NOT EXISTS jobs` + jobsTable + `;
     
     CREATE TABLE IF NOT EXISTS job_tags (
         id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -56,20 +80,224 @@ func (r *SQLiteJobsRepository) InitSchema() error {
         FOREIGN KEY(job_id) REFERENCES jobs(id) ON DELETE CASCADE
     );
     
-    CREATE INDEX IF NOT EXISTS idx_jobs_external_id ON jobs(external_id);
-    CREATE INDEX IF NOT EXISTS idx_jobs_date ON jobs(date);
     CREATE INDEX IF NOT EXISTS idx_job_tags_job_id ON job_tags(job_id);
     `
 
-	_, err := r.db.Exec(query)
-	return err
+	if _, err := r.db.Exec(query); err != nil {
+		return err
+	}
+
+	return r.migrate()
+}
+
+// addedJobColumns lists the columns added to the jobs table after its first
+// release. migrate adds the ones an existing database is missing.
+var addedJobColumns = []struct {
+	name       string
+	definition string
+}{
+	{"full_description", "TEXT NOT NULL DEFAULT ''"},
+	{"requirements", "TEXT NOT NULL DEFAULT ''"},
+	{"employer_info", "TEXT NOT NULL DEFAULT ''"},
+	{"original_url", "TEXT NOT NULL DEFAULT ''"},
+	{"salary_min", "INTEGER NOT NULL DEFAULT 0"},
+	{"salary_max", "INTEGER NOT NULL DEFAULT 0"},
+	{"salary_currency", "TEXT NOT NULL DEFAULT ''"},
+	{"salary_period", "TEXT NOT NULL DEFAULT ''"},
+	{"salary_basis", "TEXT NOT NULL DEFAULT ''"},
+	{"posted_at", "DATETIME"},
+	{"employment", "TEXT NOT NULL DEFAULT ''"},
+	{"work_mode", "TEXT NOT NULL DEFAULT ''"},
+	{"location_city", "TEXT NOT NULL DEFAULT ''"},
+	{"location_region", "TEXT NOT NULL DEFAULT ''"},
+	{"location_country", "TEXT NOT NULL DEFAULT ''"},
+	{"remote", "INTEGER NOT NULL DEFAULT 0"},
+	{"relocation", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// JobsOrder orders jobs by their posting date, the ones without it by the
+// date they were stored. idx_jobs_posted covers it.
+const JobsOrder = `COALESCE(posted_at, created_at)`
+
+// migrationIndexes are changed after the columns they use were added.
+const migrationIndexes = `
+    DROP INDEX IF EXISTS idx_jobs_date;
+    DROP INDEX IF EXISTS idx_jobs_external_id;
+    DROP INDEX IF EXISTS idx_jobs_posted_at;
+    CREATE INDEX IF NOT EXISTS idx_jobs_posted ON jobs(` + JobsOrder + `);
+    CREATE INDEX IF NOT EXISTS idx_jobs_location_city ON jobs(location_city);
+    `
+
+func (r *SQLiteJobsRepository) migrate() error {
+	existing, err := r.tableColumns("jobs")
+	if err != nil {
+		return err
+	}
+
+	for _, column := range addedJobColumns {
+		if existing[column.name] {
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE jobs ADD COLUMN %s %s", column.name, column.definition)
+		if _, err := r.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to add column %s: %w", column.name, err)
+		}
+	}
+
+	if !existing["source"] {
+		if err := r.rebuildJobs(); err != nil {
+			return fmt.Errorf("failed to key jobs by source: %w", err)
+		}
+	}
+
+	if _, err := r.db.Exec(migrationIndexes); err != nil {
+		return fmt.Errorf("failed to migrate indexes: %w", err)
+	}
+
+	return nil
+}
+
+// legacySource is the source of the jobs stored while jooble was the only
+// site watched.
+const legacySource = "jooble"
+
+// rebuildJobs moves the jobs into a table where external ids are unique per
+// source instead of globally, sqlite can't change the constraints of a table
+// in place. The ids and with them the tags are kept, foreign keys are not
+// enforced on the connection, so dropping the old table leaves the tags.
+func (r *SQLiteJobsRepository) rebuildJobs() error {
+	existing, err := r.tableColumns("jobs")
+	if err != nil {
+		return err
+	}
+	columns := make([]string, 0, len(existing))
+	for column := range existing {
+		columns = append(columns, column)
+	}
+	copied := strings.Join(columns, ", ")
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := []string{
+		"CREATE TABLE jobs_rebuild" + jobsTable,
+		fmt.Sprintf("INSERT INTO jobs_rebuild (source, %s) SELECT '%s', %s FROM jobs", copied, legacySource, copied),
+		"DROP TABLE jobs",
+		"ALTER TABLE jobs_rebuild RENAME TO jobs",
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *SQLiteJobsRepository) tableColumns(table string) (map[string]bool, error) {
+	rows, err := r.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    bool
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return nil, fmt.Errorf("failed to scan column of %s: %w", table, err)
+		}
+		columns[name] = true
+	}
+
+	return columns, rows.Err()
+}
+
+// JobColumns is the column list ScanJob expects, in its order.
+const JobColumns = `id, source, external_id, title, company, city, salary, link, description, work_type, date,
+    full_description, requirements, employer_info, original_url,
+    salary_min, salary_max, salary_currency, salary_period, salary_basis, posted_at,
+    employment, work_mode, location_city, location_region, location_country, remote, relocation`
+
+// nullTime stores an unknown time as NULL and a known one in UTC.
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+type RowScanner interface {
+	Scan(dest ...any) error
+}
+
+// ScanJob reads a row selected with JobColumns. Extra destinations receive
+// the columns selected after JobColumns.
+func ScanJob(row RowScanner, extra ...any) (domain.Job, error) {
+	var job domain.Job
+	var externalID sql.NullString
+	var postedAt sql.NullTime
+
+	dest := []any{
+		&job.ID,
+		&job.Source,
+		&externalID,
+		&job.Title,
+		&job.Company,
+		&job.City,
+		&job.Salary,
+		&job.Link,
+		&job.Description,
+		&job.WorkType,
+		&job.Date,
+		&job.FullDescription,
+		&job.Requirements,
+		&job.EmployerInfo,
+		&job.OriginalURL,
+		&job.Pay.Min,
+		&job.Pay.Max,
+		&job.Pay.Currency,
+		&job.Pay.Period,
+		&job.Pay.Basis,
+		&postedAt,
+		&job.Employment,
+		&job.WorkMode,
+		&job.Location.City,
+		&job.Location.Region,
+		&job.Location.Country,
+		&job.Location.Remote,
+		&job.Location.Relocation,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return job, err
+	}
+
+	if externalID.Valid {
+		job.ExternalID = externalID.String
+	}
+	if postedAt.Valid {
+		job.PostedAt = postedAt.Time
+	}
+
+	return job, nil
 }
 
 func (r *SQLiteJobsRepository) GetJobs() ([]domain.Job, error) {
 	query := `
-    SELECT id, external_id, title, company, city, salary, link, description, work_type, date
+    SELECT ` + JobColumns + `
     FROM jobs
-    ORDER BY created_at DESC
+    ORDER BY ` + JobsOrder + ` DESC
     `
 
 	rows, err := r.db.Query(query)
@@ -81,29 +309,11 @@ func (r *SQLiteJobsRepository) GetJobs() ([]domain.Job, error) {
 	var jobs []domain.Job
 
 	for rows.Next() {
-		var job domain.Job
-		var externalID sql.NullString
-
-		err := rows.Scan(
-			&job.ID,
-			&externalID,
-			&job.Title,
-			&job.Company,
-			&job.City,
-			&job.Salary,
-			&job.Link,
-			&job.Description,
-			&job.WorkType,
-			&job.Date,
-		)
+		job, err := ScanJob(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job: %w", err)
 		}
 
-		if externalID.Valid {
-			job.ExternalID = externalID.String
-		}
-
 		// Получаем теги
 		tags, err := r.getJobTags(job.ID)
 		if err != nil {
@@ -123,26 +333,12 @@ func (r *SQLiteJobsRepository) GetJobs() ([]domain.Job, error) {
 
 func (r *SQLiteJobsRepository) GetById(id int64) (*domain.Job, error) {
 	query := `
-    SELECT id, external_id, title, company, city, salary, link, description, work_type, date
+    SELECT ` + JobColumns + `
     FROM jobs
     WHERE id = ?
     `
 
-	var job domain.Job
-	var externalID sql.NullString
-
-	err := r.db.QueryRow(query, id).Scan(
-		&job.ID,
-		&externalID,
-		&job.Title,
-		&job.Company,
-		&job.City,
-		&job.Salary,
-		&job.Link,
-		&job.Description,
-		&job.WorkType,
-		&job.Date,
-	)
+	job, err := ScanJob(r.db.QueryRow(query, id))
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("job with id %d not found", id)
@@ -151,10 +347,6 @@ func (r *SQLiteJobsRepository) GetById(id int64) (*domain.Job, error) {
 		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
 
-	if externalID.Valid {
-		job.ExternalID = externalID.String
-	}
-
 	// Получаем теги
 	tags, err := r.getJobTags(id)
 	if err != nil {
@@ -173,11 +365,15 @@ func (r *SQLiteJobsRepository) AddJob(job domain.Job) error {
 	defer tx.Rollback()
 
 	query := `
-    INSERT INTO jobs (external_id, title, company, city, salary, link, description, work_type, date)
-    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+    INSERT INTO jobs (source, external_id, title, company, city, salary, link, description, work_type, date,
+        full_description, requirements, employer_info, original_url,
+        salary_min, salary_max, salary_currency, salary_period, salary_basis, posted_at,
+        employment, work_mode, location_city, location_region, location_country, remote, relocation)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
     `
 
 	result, err := tx.Exec(query,
+		job.Source,
 		job.ExternalID,
 		job.Title,
 		job.Company,
@@ -187,6 +383,23 @@ func (r *SQLiteJobsRepository) AddJob(job domain.Job) error {
 		job.Description,
 		job.WorkType,
 		job.Date,
+		job.FullDescription,
+		job.Requirements,
+		job.EmployerInfo,
+		job.OriginalURL,
+		job.Pay.Min,
+		job.Pay.Max,
+		job.Pay.Currency,
+		job.Pay.Period,
+		job.Pay.Basis,
+		nullTime(job.PostedAt),
+		job.Employment,
+		job.WorkMode,
+		job.Location.City,
+		job.Location.Region,
+		job.Location.Country,
+		job.Location.Remote,
+		job.Location.Relocation,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to insert job: %w", err)
@@ -218,12 +431,18 @@ func (r *SQLiteJobsRepository) UpdateJob(job domain.Job) error {
 
 	query := `
     UPDATE jobs
-    SET external_id = ?, title = ?, company = ?, city = ?, salary = ?,
-        link = ?, description = ?, work_type = ?, date = ?, updated_at = CURRENT_TIMESTAMP
+    SET source = ?, external_id = ?, title = ?, company = ?, city = ?, salary = ?,
+        link = ?, description = ?, work_type = ?, date = ?,
+        full_description = ?, requirements = ?, employer_info = ?, original_url = ?,
+        salary_min = ?, salary_max = ?, salary_currency = ?, salary_period = ?, salary_basis = ?,
+        posted_at = ?, employment = ?, work_mode = ?,
+        location_city = ?, location_region = ?, location_country = ?, remote = ?, relocation = ?,
+        updated_at = CURRENT_TIMESTAMP
     WHERE id = ?
     `
 
 	result, err := tx.Exec(query,
+		job.Source,
 		job.ExternalID,
 		job.Title,
 		job.Company,
@@ -233,6 +452,23 @@ func (r *SQLiteJobsRepository) UpdateJob(job domain.Job) error {
 		job.Description,
 		job.WorkType,
 		job.Date,
+		job.FullDescription,
+		job.Requirements,
+		job.EmployerInfo,
+		job.OriginalURL,
+		job.Pay.Min,
+		job.Pay.Max,
+		job.Pay.Currency,
+		job.Pay.Period,
+		job.Pay.Basis,
+		nullTime(job.PostedAt),
+		job.Employment,
+		job.WorkMode,
+		job.Location.City,
+		job.Location.Region,
+		job.Location.Country,
+		job.Location.Remote,
+		job.Location.Relocation,
 		job.ID,
 	)
 	if err != nil {
@@ -335,28 +571,14 @@ func (r *SQLiteJobsRepository) insertJobTags(tx *sql.Tx, jobID int64, tags []str
 	return nil
 }
 
-func (r *SQLiteJobsRepository) GetByExternalID(externalID string) (*domain.Job, error) {
+func (r *SQLiteJobsRepository) GetByExternalID(source string, externalID string) (*domain.Job, error) {
 	query := `
-    SELECT id, external_id, title, company, city, salary, link, description, work_type, date
+    SELECT ` + JobColumns + `
     FROM jobs
-    WHERE external_id = ?
+    WHERE source = ? AND external_id = ?
     `
 
-	var job domain.Job
-	var extID sql.NullString
-
-	err := r.db.QueryRow(query, externalID).Scan(
-		&job.ID,
-		&extID,
-		&job.Title,
-		&job.Company,
-		&job.City,
-		&job.Salary,
-		&job.Link,
-		&job.Description,
-		&job.WorkType,
-		&job.Date,
-	)
+	job, err := ScanJob(r.db.QueryRow(query, source, externalID))
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -365,10 +587,6 @@ func (r *SQLiteJobsRepository) GetByExternalID(externalID string) (*domain.Job,
 		return nil, fmt.Errorf("failed to get job by external id: %w", err)
 	}
 
-	if extID.Valid {
-		job.ExternalID = extID.String
-	}
-
 	tags, err := r.getJobTags(job.ID)
 	if err != nil {
 		return nil, err
@@ -378,10 +596,10 @@ func (r *SQLiteJobsRepository) GetByExternalID(externalID string) (*domain.Job,
 	return &job, nil
 }
 
-func (r *SQLiteJobsRepository) JobExists(externalID string) (bool, error) {
+func (r *SQLiteJobsRepository) JobExists(source string, externalID string) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM jobs WHERE external_id = ?)`
-	err := r.db.QueryRow(query, externalID).Scan(&exists)
+	query := `SELECT EXISTS(SELECT 1 FROM jobs WHERE source = ? AND external_id = ?)`
+	err := r.db.QueryRow(query, source, externalID).Scan(&exists)
 	return exists, err
 }
 
@@ -391,3 +609,33 @@ func (r *SQLiteJobsRepository) Count() (int64, error) {
 	err := r.db.QueryRow(query).Scan(&count)
 	return count, err
 }
+
+// CountByCity counts the stored jobs per canonical city, jobs whose city is
+// unknown are counted under "".
+func (r *SQLiteJobsRepository) CountByCity() (map[string]int64, error) {
+	query := `SELECT location_city, COUNT(*) FROM jobs GROUP BY location_city`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs by city: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var (
+			city  string
+			count int64
+		)
+		if err := rows.Scan(&city, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan city count: %w", err)
+		}
+		counts[city] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return counts, nil
+}
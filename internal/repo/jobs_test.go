@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"database/sql"
+	"jooble-parser/internal/domain"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// openLegacy opens a temporary database holding the jobs of testdata/legacy.sql.
+func openLegacy(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	fixture, err := os.ReadFile(filepath.Join("testdata", "legacy.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(fixture)); err != nil {
+		t.Fatalf("failed to load fixture: %v", err)
+	}
+	return db
+}
+
+func TestInitSchemaMigratesLegacyJobs(t *testing.T) {
+	db := openLegacy(t)
+	repository := NewSQLiteJobsRepository(db)
+
+	if err := repository.InitSchema(); err != nil {
+		t.Fatalf("InitSchema() error = %v", err)
+	}
+
+	jobs, err := repository.GetJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("got %d jobs, want 3", len(jobs))
+	}
+
+	job, err := repository.GetById(5)
+	if err != nil {
+		t.Fatalf("GetById(5) error = %v", err)
+	}
+	if job.Source != legacySource || job.ExternalID != "6097966535073320088" || job.Title != "Backend Developer in AI Team" {
+		t.Errorf("GetById(5) = %q %q %q, want the legacy job kept with source %q",
+			job.Source, job.ExternalID, job.Title, legacySource)
+	}
+	if !slices.Equal(job.Tags, []string{"Гнучкий графік"}) {
+		t.Errorf("GetById(5).Tags = %v, want the legacy tags", job.Tags)
+	}
+
+	job, err = repository.GetByExternalID(legacySource, "464575341174131141")
+	if err != nil {
+		t.Fatalf("GetByExternalID() error = %v", err)
+	}
+	if job == nil || job.ID != 1 || len(job.Tags) != 2 {
+		t.Errorf("GetByExternalID() = %+v, want job 1 with its 2 tags", job)
+	}
+
+	// the migration runs once, a second start finds the schema up to date
+	if err := repository.InitSchema(); err != nil {
+		t.Fatalf("second InitSchema() error = %v", err)
+	}
+	if count, err := repository.Count(); err != nil || count != 3 {
+		t.Errorf("Count() = %d, %v after a second InitSchema, want 3", count, err)
+	}
+}
+
+func TestMigratedJobsAreUniquePerSource(t *testing.T) {
+	db := openLegacy(t)
+	repository := NewSQLiteJobsRepository(db)
+	if err := repository.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	other := domain.Job{Source: "dou", ExternalID: "464575341174131141", Title: "Go Developer"}
+	if err := repository.AddJob(other); err != nil {
+		t.Errorf("AddJob() of another source with a taken id error = %v", err)
+	}
+
+	again := domain.Job{Source: legacySource, ExternalID: "464575341174131141", Title: "Senior Golang Developer"}
+	if err := repository.AddJob(again); err == nil {
+		t.Error("AddJob() of a stored job succeeded, want a unique constraint error")
+	}
+
+	exists, err := repository.JobExists("dou", "464575341174131141")
+	if err != nil || !exists {
+		t.Errorf("JobExists(dou) = %v, %v, want true", exists, err)
+	}
+	exists, err = repository.JobExists("workua", "464575341174131141")
+	if err != nil || exists {
+		t.Errorf("JobExists(workua) = %v, %v, want false", exists, err)
+	}
+}
+
+func TestGetJobsByPostingDate(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "data.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repository := NewSQLiteJobsRepository(db)
+	if err := repository.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a job without a posting date is as new as it is stored
+	jobs := []domain.Job{
+		{Source: legacySource, ExternalID: "older", PostedAt: time.Date(2025, time.October, 20, 0, 0, 0, 0, time.UTC)},
+		{Source: legacySource, ExternalID: "undated"},
+		{Source: legacySource, ExternalID: "newer", PostedAt: time.Date(2025, time.October, 22, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, job := range jobs {
+		if err := repository.AddJob(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stored, err := repository.GetJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, len(stored))
+	for i, job := range stored {
+		ids[i] = job.ExternalID
+	}
+	if want := []string{"undated", "newer", "older"}; !slices.Equal(ids, want) {
+		t.Errorf("GetJobs() = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,39 @@
+-- schema and jobs of a database written while jooble was the only site
+CREATE TABLE jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    external_id TEXT UNIQUE,
+    title TEXT NOT NULL,
+    company TEXT,
+    city TEXT,
+    salary TEXT,
+    link TEXT,
+    description TEXT,
+    work_type TEXT,
+    date TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE job_tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id INTEGER,
+    tag TEXT,
+    FOREIGN KEY(job_id) REFERENCES jobs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_jobs_external_id ON jobs(external_id);
+CREATE INDEX idx_jobs_date ON jobs(date);
+CREATE INDEX idx_job_tags_job_id ON job_tags(job_id);
+
+INSERT INTO jobs (id, external_id, title, company, city, salary, link, description, work_type, date) VALUES
+    (1, '464575341174131141', 'Senior Golang Developer', 'Empat', 'за кордоном', '',
+     'https://ua.jooble.org/desc/464575341174131141?ckey=golang+developer', 'Go, PostgreSQL, Kubernetes', 'Повна зайнятість', '24 жовтня 2025'),
+    (2, '-4134916308710032189', 'Software Backend Engineer III', 'Box Inc.', 'Варшава, Польща', '$5000–6000',
+     'https://ua.jooble.org/desc/-4134916308710032189?ckey=golang+developer', '', '', '2 дні тому'),
+    (5, '6097966535073320088', 'Backend Developer in AI Team', 'T-Mobile Polska', 'Варшава, Польща', '',
+     'https://ua.jooble.org/desc/6097966535073320088?ckey=golang+developer', '', '', '');
+
+INSERT INTO job_tags (job_id, tag) VALUES
+    (1, 'Віддалена робота'),
+    (1, 'Повна зайнятість'),
+    (5, 'Гнучкий графік');
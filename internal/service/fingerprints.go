@@ -0,0 +1,72 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"jooble-parser/internal/repo"
+
+	_ "github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+)
+
+type FingerprintService interface {
+	// Unchanged reports whether hash matches the last saved fingerprint of url
+	// and records the check if it does.
+	Unchanged(url string, hash string) (bool, error)
+	Save(url string, hash string) error
+}
+
+type SqliteFingerprintService struct {
+	repo   repo.FingerprintsRepository
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+func NewSqliteFingerprintService(pathToDb string, logger *zap.Logger) (FingerprintService, error) {
+	db, err := sql.Open("sqlite3", pathToDb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	repository := repo.NewSQLiteFingerprintsRepository(db)
+
+	if err := repository.InitSchema(); err != nil {
+		return nil, fmt.Errorf("failed to init schema: %w", err)
+	}
+
+	return &SqliteFingerprintService{
+		repo:   repository,
+		db:     db,
+		logger: logger,
+	}, nil
+}
+
+func (s *SqliteFingerprintService) Unchanged(url string, hash string) (bool, error) {
+	last, err := s.repo.GetFingerprint(url)
+	if err != nil {
+		return false, err
+	}
+	if last == "" || last != hash {
+		return false, nil
+	}
+
+	if err := s.repo.TouchFingerprint(url); err != nil {
+		s.logger.Warn("Failed to record fingerprint check", zap.Error(err))
+	}
+	return true, nil
+}
+
+func (s *SqliteFingerprintService) Save(url string, hash string) error {
+	return s.repo.SaveFingerprint(url, hash)
+}
+
+func (s *SqliteFingerprintService) Close() error {
+	if s.db != nil {
+		return s.db.Close()
+	}
+	return nil
+}
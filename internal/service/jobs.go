@@ -17,9 +17,10 @@ type JobService interface {
 	UpdateJob(job domain.Job) error
 	DeleteJob(id int64) error
 
-	GetByExternalID(externalID string) (*domain.Job, error)
-	JobExists(externalID string) (bool, error)
+	GetByExternalID(source string, externalID string) (*domain.Job, error)
+	JobExists(source string, externalID string) (bool, error)
 	Count() (int64, error)
+	CountByCity() (map[string]int64, error)
 
 	AddJobWithLimit(job domain.Job) error
 	CleanupOldJobs() error
@@ -73,7 +74,7 @@ func (s *SqliteJobService) AddJob(job domain.Job) error {
 }
 
 func (s *SqliteJobService) AddJobWithLimit(job domain.Job) error {
-	exists, err := s.repo.JobExists(job.ExternalID)
+	exists, err := s.repo.JobExists(job.Source, job.ExternalID)
 	if err != nil {
 		return fmt.Errorf("failed to check job existence: %w", err)
 	}
@@ -104,18 +105,22 @@ func (s *SqliteJobService) DeleteJob(id int64) error {
 	return s.repo.DeleteJob(id)
 }
 
-func (s *SqliteJobService) GetByExternalID(externalID string) (*domain.Job, error) {
-	return s.repo.GetByExternalID(externalID)
+func (s *SqliteJobService) GetByExternalID(source string, externalID string) (*domain.Job, error) {
+	return s.repo.GetByExternalID(source, externalID)
 }
 
-func (s *SqliteJobService) JobExists(externalID string) (bool, error) {
-	return s.repo.JobExists(externalID)
+func (s *SqliteJobService) JobExists(source string, externalID string) (bool, error) {
+	return s.repo.JobExists(source, externalID)
 }
 
 func (s *SqliteJobService) Count() (int64, error) {
 	return s.repo.Count()
 }
 
+func (s *SqliteJobService) CountByCity() (map[string]int64, error) {
+	return s.repo.CountByCity()
+}
+
 func (s *SqliteJobService) CleanupOldJobs() error {
 	count, err := s.repo.Count()
 	if err != nil {
@@ -151,7 +156,7 @@ func (s *SqliteJobService) deleteOldestJobs(count int64) error {
 	}
 
 	if deleted > 0 {
-		fmt.Printf("Deleted %d old jobs\n", deleted)
+		s.logger.Info("Deleted old jobs", zap.Int64("count", deleted))
 	}
 
 	return nil
@@ -166,9 +171,9 @@ func (s *SqliteJobService) Close() error {
 
 func (s *SqliteJobService) GetOldestJobs(limit int) ([]domain.Job, error) {
 	query := `
-	SELECT id, external_id, title, company, city, salary, link, description, work_type, date, created_at
+	SELECT ` + repo.JobColumns + `, created_at
 	FROM jobs
-	ORDER BY created_at ASC
+	ORDER BY ` + repo.JobsOrder + ` ASC
 	LIMIT ?
 	`
 
@@ -180,31 +185,13 @@ func (s *SqliteJobService) GetOldestJobs(limit int) ([]domain.Job, error) {
 
 	var jobs []domain.Job
 	for rows.Next() {
-		var job domain.Job
-		var externalID sql.NullString
 		var createdAt string
 
-		err := rows.Scan(
-			&job.ID,
-			&externalID,
-			&job.Title,
-			&job.Company,
-			&job.City,
-			&job.Salary,
-			&job.Link,
-			&job.Description,
-			&job.WorkType,
-			&job.Date,
-			&createdAt,
-		)
+		job, err := repo.ScanJob(rows, &createdAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job: %w", err)
 		}
 
-		if externalID.Valid {
-			job.ExternalID = externalID.String
-		}
-
 		jobs = append(jobs, job)
 	}
 
@@ -213,9 +200,9 @@ func (s *SqliteJobService) GetOldestJobs(limit int) ([]domain.Job, error) {
 
 func (s *SqliteJobService) GetNewestJobs(limit int) ([]domain.Job, error) {
 	query := `
-	SELECT id, external_id, title, company, city, salary, link, description, work_type, date
+	SELECT ` + repo.JobColumns + `
 	FROM jobs
-	ORDER BY created_at DESC
+	ORDER BY ` + repo.JobsOrder + ` DESC
 	LIMIT ?
 	`
 
@@ -227,29 +214,11 @@ func (s *SqliteJobService) GetNewestJobs(limit int) ([]domain.Job, error) {
 
 	var jobs []domain.Job
 	for rows.Next() {
-		var job domain.Job
-		var externalID sql.NullString
-
-		err := rows.Scan(
-			&job.ID,
-			&externalID,
-			&job.Title,
-			&job.Company,
-			&job.City,
-			&job.Salary,
-			&job.Link,
-			&job.Description,
-			&job.WorkType,
-			&job.Date,
-		)
+		job, err := repo.ScanJob(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan job: %w", err)
 		}
 
-		if externalID.Valid {
-			job.ExternalID = externalID.String
-		}
-
 		jobs = append(jobs, job)
 	}
 
@@ -41,6 +41,15 @@ func (u *BotUpdateSignal) Signal(jobs []domain.Job) error {
 	return nil
 }
 
+func (u *BotUpdateSignal) Alert(message string) error {
+	text := fmt.Sprintf("⚠️ <b>Проблема с парсером</b>\n\n%s", escapeHTML(message))
+	if err := u.sendMessage(text, ""); err != nil {
+		return fmt.Errorf("failed to send alert: %w", err)
+	}
+	u.logger.Debug("Successfully sent alert")
+	return nil
+}
+
 func (u *BotUpdateSignal) formatJobMessage(job domain.Job) string {
 	var sb strings.Builder
 
@@ -52,19 +61,25 @@ func (u *BotUpdateSignal) formatJobMessage(job domain.Job) string {
 		sb.WriteString(fmt.Sprintf("📍 %s\n", escapeHTML(job.City)))
 	}
 
+	if job.Location.Relocation {
+		sb.WriteString("✈️ Релокация\n")
+	}
+
 	if job.Salary != "" {
 		sb.WriteString(fmt.Sprintf("💰 %s\n", escapeHTML(job.Salary)))
 	}
 
-	if job.WorkType != "" {
-		sb.WriteString(fmt.Sprintf("💼 %s\n", escapeHTML(job.WorkType)))
+	if workType := formatWorkType(job); workType != "" {
+		sb.WriteString(fmt.Sprintf("💼 %s\n", escapeHTML(workType)))
 	}
 
 	if job.Date != "" {
 		sb.WriteString(fmt.Sprintf("📅 %s\n", escapeHTML(job.Date)))
 	}
 
-	if job.Description != "" {
+	if job.FullDescription != "" {
+		sb.WriteString(fmt.Sprintf("\n%s\n", escapeHTML(truncate(job.FullDescription, 1000))))
+	} else if job.Description != "" {
 		description := job.Description
 		if len(description) > 400 {
 			description = description[:397] + "..."
@@ -72,6 +87,19 @@ func (u *BotUpdateSignal) formatJobMessage(job domain.Job) string {
 		sb.WriteString(fmt.Sprintf("\n%s\n", escapeHTML(description)))
 	}
 
+	if job.Requirements != "" {
+		sb.WriteString(fmt.Sprintf("\n<b>Требования:</b>\n%s\n", escapeHTML(truncate(job.Requirements, 800))))
+	}
+
+	if job.EmployerInfo != "" {
+		sb.WriteString(fmt.Sprintf("\n<b>О компании:</b>\n%s\n", escapeHTML(truncate(job.EmployerInfo, 400))))
+	}
+
+	if job.OriginalURL != "" {
+		sb.WriteString(fmt.Sprintf("\n🌐 <a href=\"%s\">Оригинал вакансии</a>\n",
+			strings.ReplaceAll(escapeHTML(job.OriginalURL), `"`, "&quot;")))
+	}
+
 	if len(job.Tags) > 0 {
 		sb.WriteString("\n")
 		hashtags := make([]string, 0, len(job.Tags))
@@ -88,6 +116,35 @@ func (u *BotUpdateSignal) formatJobMessage(job domain.Job) string {
 	return sb.String()
 }
 
+var employmentLabels = map[domain.EmploymentType]string{
+	domain.EmploymentFullTime:   "Полная занятость",
+	domain.EmploymentPartTime:   "Частичная занятость",
+	domain.EmploymentContract:   "Контракт",
+	domain.EmploymentInternship: "Стажировка",
+}
+
+var workModeLabels = map[domain.WorkMode]string{
+	domain.WorkModeRemote: "Удалённо",
+	domain.WorkModeHybrid: "Гибрид",
+	domain.WorkModeOnsite: "Офис",
+}
+
+// formatWorkType prefers the normalized employment type and work mode to the
+// text of the card.
+func formatWorkType(job domain.Job) string {
+	var parts []string
+	if label, ok := employmentLabels[job.Employment]; ok {
+		parts = append(parts, label)
+	}
+	if label, ok := workModeLabels[job.WorkMode]; ok {
+		parts = append(parts, label)
+	}
+	if len(parts) == 0 {
+		return job.WorkType
+	}
+	return strings.Join(parts, " · ")
+}
+
 func (u *BotUpdateSignal) sendMessage(text string, link string) error {
 	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", u.token)
 
@@ -131,6 +188,14 @@ func (u *BotUpdateSignal) sendMessage(text string, link string) error {
 	return nil
 }
 
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-3]) + "..."
+}
+
 func escapeHTML(s string) string {
 	s = strings.ReplaceAll(s, "&", "&amp;")
 	s = strings.ReplaceAll(s, "<", "&lt;")
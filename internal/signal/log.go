@@ -18,3 +18,9 @@ func (signal *LogUpdateSignal) Signal(job []domain.Job) error {
 	fmt.Println(job)
 	return nil
 }
+
+func (signal *LogUpdateSignal) Alert(message string) error {
+	fmt.Println("Log Alert Signal")
+	fmt.Println(message)
+	return nil
+}
@@ -4,4 +4,5 @@ import "jooble-parser/internal/domain"
 
 type UpdateSignal interface {
 	Signal(job []domain.Job) error
+	Alert(message string) error
 }
@@ -0,0 +1,96 @@
+package sites
+
+import (
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/loader"
+	"jooble-parser/internal/parser/setters"
+)
+
+// Names of the built-in sites.
+const (
+	Jooble = "jooble"
+	WorkUa = "workua"
+	Djinni = "djinni"
+	Dou    = "dou"
+)
+
+var trim = []setters.Transform{{Kind: setters.TransformTrim}}
+
+// idFrom reads the external id from the first group of pattern in the link.
+func idFrom(selector string, pattern string) setters.FieldSpec {
+	return setters.FieldSpec{
+		Field:      setters.FieldID,
+		Selector:   selector,
+		Attr:       "href",
+		Required:   true,
+		Transforms: []setters.Transform{{Kind: setters.TransformRegex, Pattern: pattern}},
+	}
+}
+
+// builtin returns the sites known without configuration. Jooble renders its
+// results with scripts and is loaded as loader.kind says, the other boards
+// serve plain html.
+func builtin() []Site {
+	return []Site{
+		{
+			Name:  Jooble,
+			Hosts: []string{"jooble.org"},
+			Spec:  &setters.DefaultSpec,
+		},
+		{
+			Name:   WorkUa,
+			Hosts:  []string{"work.ua"},
+			Loader: config.LoaderHttp,
+			Layout: loader.Layout{PageParam: "page"},
+			Spec: &setters.Spec{
+				Card: "div.card.job-link",
+				Fields: []setters.FieldSpec{
+					idFrom("h2 a", `/jobs/(\d+)`),
+					{Field: "title", Selector: "h2 a", Transforms: trim, Required: true},
+					{Field: "link", Selector: "h2 a", Attr: "href", Transforms: trim, Required: true},
+					{Field: "company", Selector: "div.mt-xs span.strong-600", Transforms: trim},
+					{Field: "salary", Selector: "div > span.strong-600", Transforms: trim},
+					{Field: "description", Selector: "p.ellipsis", Transforms: trim},
+				},
+			},
+		},
+		{
+			Name:   Djinni,
+			Hosts:  []string{"djinni.co"},
+			Loader: config.LoaderHttp,
+			Layout: loader.Layout{PageParam: "page"},
+			Spec: &setters.Spec{
+				Card: `li[id^="job-item-"]`,
+				Fields: []setters.FieldSpec{
+					idFrom("a.job-item__title-link", `/jobs/(\d+)`),
+					{Field: "title", Selector: "a.job-item__title-link", Transforms: trim, Required: true},
+					{Field: "link", Selector: "a.job-item__title-link", Attr: "href", Transforms: trim, Required: true},
+					{Field: "company", Selector: "a.text-body.js-analytics-event", Transforms: trim},
+					{Field: "city", Selector: "span.location-text", Transforms: trim},
+					{Field: "salary", Selector: "span.public-salary-item", Transforms: trim},
+					{Field: "description", Selector: "div.js-original-text", Transforms: trim},
+				},
+			},
+		},
+		{
+			Name:   Dou,
+			Hosts:  []string{"jobs.dou.ua"},
+			Loader: config.LoaderHttp,
+			Layout: loader.Layout{SinglePage: true}, // more vacancies are fetched by a script
+			Spec: &setters.Spec{
+				List: "div#vacancyListId",
+				Card: "li.l-vacancy",
+				Fields: []setters.FieldSpec{
+					idFrom("a.vt", `/vacancies/(\d+)`),
+					{Field: "title", Selector: "a.vt", Transforms: trim, Required: true},
+					{Field: "link", Selector: "a.vt", Attr: "href", Transforms: trim, Required: true},
+					{Field: "company", Selector: "a.company", Transforms: trim},
+					{Field: "city", Selector: "span.cities", Transforms: trim},
+					{Field: "salary", Selector: "span.salary", Transforms: trim},
+					{Field: "date", Selector: "div.date", Transforms: trim},
+					{Field: "description", Selector: "div.sh-info", Transforms: trim},
+				},
+			},
+		},
+	}
+}
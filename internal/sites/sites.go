@@ -0,0 +1,119 @@
+package sites
+
+import (
+	"fmt"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/loader"
+	"jooble-parser/internal/parser/setters"
+	"net/url"
+	"strings"
+)
+
+// Site is a job board the html source can watch.
+type Site struct {
+	Name   string   // namespace of the external ids of its jobs, stored as their source
+	Hosts  []string // subdomains of a host belong to the site
+	Loader string   // config.LoaderChrome or config.LoaderHttp, loader.kind when empty
+	Layout loader.Layout
+	Spec   *setters.Spec
+}
+
+// Registry finds the site of a search url.
+type Registry struct {
+	sites []*Site
+}
+
+// NewRegistry builds the registry of the built-in sites changed and extended
+// by the sites of the config. The selector spec of parsing.selectors replaces
+// the one of jooble.
+func NewRegistry(cfg *config.Config) (*Registry, error) {
+	registry := &Registry{}
+	for _, site := range builtin() {
+		registry.sites = append(registry.sites, &site)
+	}
+
+	if cfg.Parsing.Selectors != "" {
+		spec, err := setters.LoadSpec(cfg.Parsing.Selectors)
+		if err != nil {
+			return nil, err
+		}
+		registry.find(Jooble).Spec = spec
+	}
+
+	for _, siteCfg := range cfg.Sites {
+		site := registry.find(siteCfg.Name)
+		if site == nil {
+			site = &Site{Name: siteCfg.Name}
+			registry.sites = append(registry.sites, site)
+		}
+		if err := site.apply(&siteCfg); err != nil {
+			return nil, fmt.Errorf("site %s: %w", siteCfg.Name, err)
+		}
+	}
+
+	for _, site := range registry.sites {
+		if len(site.Hosts) == 0 {
+			return nil, fmt.Errorf("site %s: at least one host is required", site.Name)
+		}
+		if site.Spec == nil {
+			return nil, fmt.Errorf("site %s: a selector spec is required", site.Name)
+		}
+		site.Layout.Cards = site.Spec.Card
+	}
+
+	return registry, nil
+}
+
+func (s *Site) apply(cfg *config.SiteConfig) error {
+	if len(cfg.Hosts) > 0 {
+		s.Hosts = cfg.Hosts
+	}
+	if cfg.Loader != "" {
+		s.Loader = cfg.Loader
+	}
+	if len(cfg.Waits) > 0 {
+		s.Layout.Waits = cfg.Waits
+	}
+	if cfg.PageParam != "" {
+		s.Layout.PageParam = cfg.PageParam
+	}
+	if cfg.MoreSelector != "" {
+		s.Layout.MoreSelector = cfg.MoreSelector
+	}
+	if cfg.Selectors != "" {
+		spec, err := setters.LoadSpec(cfg.Selectors)
+		if err != nil {
+			return err
+		}
+		s.Spec = spec
+	}
+	return nil
+}
+
+func (r *Registry) find(name string) *Site {
+	for _, site := range r.sites {
+		if site.Name == name {
+			return site
+		}
+	}
+	return nil
+}
+
+// Lookup returns the site serving rawURL.
+func (r *Registry) Lookup(rawURL string) (*Site, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search url %s: %w", rawURL, err)
+	}
+	host := strings.ToLower(u.Hostname())
+
+	for _, site := range r.sites {
+		for _, siteHost := range site.Hosts {
+			if host == siteHost || strings.HasSuffix(host, "."+siteHost) {
+				return site, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no site is known for %s, add it to sites", host)
+}
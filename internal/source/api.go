@@ -0,0 +1,190 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"jooble-parser/internal/config"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/loader"
+	"jooble-parser/internal/parser"
+	"jooble-parser/internal/parser/setters"
+	"jooble-parser/internal/sites"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// ApiSource reads jobs from the Jooble partner API instead of scraping
+	// the search page.
+	ApiSource struct {
+		client   *http.Client
+		endpoint string
+		keywords string
+		location string
+		paging   config.PagingConfig
+		logger   *zap.Logger
+	}
+
+	apiRequest struct {
+		Keywords string `json:"keywords"`
+		Location string `json:"location,omitempty"`
+		Page     string `json:"page,omitempty"`
+	}
+
+	apiResponse struct {
+		TotalCount int      `json:"totalCount"`
+		Jobs       []apiJob `json:"jobs"`
+	}
+
+	apiJob struct {
+		ID       json.Number `json:"id"`
+		Title    string      `json:"title"`
+		Location string      `json:"location"`
+		Snippet  string      `json:"snippet"`
+		Salary   string      `json:"salary"`
+		Type     string      `json:"type"`
+		Link     string      `json:"link"`
+		Company  string      `json:"company"`
+		Updated  string      `json:"updated"`
+	}
+)
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+func NewApiSource(cfg *config.Config, logger *zap.Logger) JobSource {
+	return &ApiSource{
+		client: &http.Client{
+			Timeout: cfg.Api.GetTimeoutDuration(),
+		},
+		endpoint: strings.TrimRight(cfg.Api.BaseUrl, "/") + "/" + cfg.Api.Key,
+		keywords: cfg.Api.Keywords,
+		location: cfg.Api.Location,
+		paging:   cfg.Loader.Paging,
+		logger:   logger,
+	}
+}
+
+func (s *ApiSource) Fetch(ctx context.Context) ([]domain.Job, error) {
+	var (
+		jobs    []domain.Job
+		skipped []error
+		fetched int
+	)
+
+	for page := 1; page <= s.paging.MaxPages; page++ {
+		resp, err := s.request(ctx, page)
+		if err != nil {
+			if page > 1 {
+				s.logger.Warn("Failed to load next api page", zap.Int("page", page), zap.Error(err))
+				break
+			}
+			return nil, err
+		}
+
+		for _, item := range resp.Jobs {
+			job := item.toDomain()
+			if job.ExternalID == "" {
+				// a job without an id could never be told apart from the others
+				skipped = append(skipped, parser.CardError{Card: fetched, Field: setters.FieldID, Required: true, Err: setters.ErrMissing})
+			} else {
+				jobs = append(jobs, job)
+			}
+			fetched++
+		}
+
+		if len(resp.Jobs) == 0 || fetched >= resp.TotalCount {
+			break
+		}
+		if s.paging.MaxCards > 0 && len(jobs) >= s.paging.MaxCards {
+			break
+		}
+	}
+
+	if len(skipped) > 0 {
+		s.logger.Warn("Some api jobs were skipped",
+			zap.Int("rejected", len(skipped)),
+			zap.Int("kept", len(jobs)),
+			zap.Errors("errors", skipped))
+	}
+
+	if len(jobs) == 0 {
+		return nil, loader.ErrEmptyResults
+	}
+
+	s.logger.Debug("Fetched api jobs", zap.Int("jobs", len(jobs)))
+	return jobs, nil
+}
+
+func (s *ApiSource) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+func (s *ApiSource) request(ctx context.Context, page int) (*apiResponse, error) {
+	body, err := json.Marshal(apiRequest{
+		Keywords: s.keywords,
+		Location: s.location,
+		Page:     strconv.Itoa(page),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal api request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return nil, fmt.Errorf("%w: jooble api: %v", loader.ErrTimeout, err)
+		}
+		return nil, fmt.Errorf("jooble api error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests:
+		return nil, fmt.Errorf("%w: jooble api status %d", loader.ErrBlocked, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("jooble api status %d", resp.StatusCode)
+	}
+
+	var result apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode api response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (job apiJob) toDomain() domain.Job {
+	return domain.Job{
+		Source:      sites.Jooble,
+		ExternalID:  job.ID.String(),
+		Title:       stripTags(job.Title),
+		Company:     strings.TrimSpace(job.Company),
+		City:        strings.TrimSpace(job.Location),
+		Salary:      strings.TrimSpace(job.Salary),
+		Link:        strings.TrimSpace(job.Link),
+		Description: stripTags(job.Snippet),
+		WorkType:    strings.TrimSpace(job.Type),
+		Date:        strings.TrimSpace(job.Updated),
+	}
+}
+
+func stripTags(s string) string {
+	return strings.TrimSpace(html.UnescapeString(tagPattern.ReplaceAllString(s, "")))
+}
@@ -0,0 +1,135 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"jooble-parser/internal/artifacts"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/loader"
+	"jooble-parser/internal/parser"
+	"jooble-parser/internal/service"
+	"jooble-parser/internal/sites"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// HtmlSource loads a search page of a site and scrapes the job cards from it.
+type HtmlSource struct {
+	site         *sites.Site
+	loader       loader.HtmlLoader
+	parser       *parser.JobParser
+	url          string
+	fingerprints service.FingerprintService
+	artifacts    artifacts.Store
+	logger       *zap.Logger
+
+	pending string // fingerprint of the last fetch, saved by Commit
+}
+
+func NewHtmlSource(site *sites.Site,
+	loader loader.HtmlLoader,
+	parser *parser.JobParser,
+	url string,
+	fingerprints service.FingerprintService,
+	store artifacts.Store,
+	logger *zap.Logger) JobSource {
+
+	return &HtmlSource{
+		site:         site,
+		loader:       loader,
+		parser:       parser,
+		url:          url,
+		fingerprints: fingerprints,
+		artifacts:    store,
+		logger:       logger,
+	}
+}
+
+func (s *HtmlSource) Fetch(ctx context.Context) ([]domain.Job, error) {
+	s.pending = ""
+
+	layout := s.site.Layout
+	layout.Postings = s.parser.HasPostings
+
+	html, err := s.loader.Load(s.url, loader.WithLayout(ctx, layout))
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := s.parser.Fingerprint(html)
+	if err != nil {
+		return nil, s.keepArtifacts(ctx, html, fmt.Errorf("parser error: %w", err))
+	}
+	if fingerprint != "" {
+		unchanged, err := s.fingerprints.Unchanged(s.url, fingerprint)
+		if err != nil {
+			s.logger.Warn("Failed to check fingerprint", zap.Error(err))
+		} else if unchanged {
+			return nil, ErrUnchanged
+		}
+	}
+
+	result, err := s.parser.Parse(html)
+	if err != nil {
+		return nil, s.keepArtifacts(ctx, html, fmt.Errorf("parser error: %w", err))
+	}
+
+	health := result.Health
+	s.logger.Debug("Parse health",
+		zap.Int("cards", health.Cards),
+		zap.Any("fill_rate", health.FillRate))
+	if health.Degraded {
+		err := fmt.Errorf("%w: %s: %s", parser.ErrDegraded, s.url, health)
+		return nil, s.keepArtifacts(ctx, html, err)
+	}
+
+	if len(result.Errors) > 0 {
+		errs := make([]error, len(result.Errors))
+		for i, cardErr := range result.Errors {
+			errs[i] = cardErr
+		}
+		s.logger.Warn("Some job cards were not read completely",
+			zap.Int("rejected", result.Rejected()),
+			zap.Int("kept", len(result.Jobs)),
+			zap.Errors("errors", errs))
+	}
+
+	for i := range result.Jobs {
+		result.Jobs[i].Source = s.site.Name
+		result.Jobs[i].Link = resolveLink(s.url, result.Jobs[i].Link)
+	}
+
+	s.pending = fingerprint
+	return result.Jobs, nil
+}
+
+// Commit saves the fingerprint of the last fetch, so an unchanged page is
+// skipped from now on.
+func (s *HtmlSource) Commit() error {
+	if s.pending == "" {
+		return nil
+	}
+
+	if err := s.fingerprints.Save(s.url, s.pending); err != nil {
+		return err
+	}
+	s.pending = ""
+	return nil
+}
+
+func (s *HtmlSource) Close() error {
+	return s.loader.Close()
+}
+
+func (s *HtmlSource) keepArtifacts(ctx context.Context, html string, err error) error {
+	path, saveErr := s.artifacts.Save(ctx, "page.html", []byte(html))
+	if saveErr != nil {
+		s.logger.Warn("Failed to save page html", zap.Error(saveErr))
+		return err
+	}
+	if path == "" {
+		return err
+	}
+	return artifacts.Wrap(err, filepath.Dir(path))
+}
@@ -0,0 +1,23 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"jooble-parser/internal/domain"
+)
+
+// ErrUnchanged is returned by Fetch when the results are the same as the last
+// committed ones and there is nothing to parse or compare.
+var ErrUnchanged = errors.New("results unchanged")
+
+type JobSource interface {
+	Fetch(ctx context.Context) ([]domain.Job, error)
+	Close() error
+}
+
+// Committer is implemented by sources that remember what they fetched. Commit
+// is called once the fetched jobs were stored, so a failed run is fetched
+// again in full.
+type Committer interface {
+	Commit() error
+}
@@ -0,0 +1,163 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"jooble-parser/internal/domain"
+	"jooble-parser/internal/loader"
+	"jooble-parser/internal/parser"
+	"jooble-parser/internal/parser/setters"
+	"jooble-parser/internal/sites"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// XhrSource opens the search page in Chrome and decodes the search
+	// results JSON the page requests for itself, so it does not depend on
+	// the markup of the job cards.
+	XhrSource struct {
+		loader  loader.ResponseCapturer
+		url     string
+		pattern string
+		logger  *zap.Logger
+	}
+
+	xhrPayload struct {
+		Jobs []xhrJob `json:"jobs"`
+	}
+
+	xhrJob struct {
+		Uid         json.Number `json:"uid"`
+		Position    string      `json:"position"`
+		Url         string      `json:"url"`
+		Content     string      `json:"content"`
+		Salary      string      `json:"salary"`
+		JobType     string      `json:"jobType"`
+		DateCaption string      `json:"dateCaption"`
+		Company     xhrNamed    `json:"company"`
+		Location    xhrNamed    `json:"location"`
+		Tags        []xhrNamed  `json:"tags"`
+	}
+
+	// xhrNamed accepts both a plain string and an object with a name.
+	xhrNamed struct {
+		Name string `json:"name"`
+	}
+)
+
+func NewXhrSource(loader loader.ResponseCapturer, pageUrl string, pattern string, logger *zap.Logger) JobSource {
+	return &XhrSource{
+		loader:  loader,
+		url:     pageUrl,
+		pattern: pattern,
+		logger:  logger,
+	}
+}
+
+func (s *XhrSource) Fetch(ctx context.Context) ([]domain.Job, error) {
+	bodies, err := s.loader.Capture(s.url, ctx, func(responseURL string) bool {
+		return strings.Contains(responseURL, s.pattern)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(bodies) == 0 {
+		return nil, fmt.Errorf("%w: no response matched %q", loader.ErrLayoutChanged, s.pattern)
+	}
+
+	var (
+		jobs    []domain.Job
+		skipped []error
+	)
+	seen := make(map[string]struct{})
+
+	for _, body := range bodies {
+		var payload xhrPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			s.logger.Warn("Failed to decode search results payload", zap.Error(err))
+			continue
+		}
+
+		for i, item := range payload.Jobs {
+			job := item.toDomain(s.url)
+			if job.ExternalID == "" {
+				// a job without an id could never be told apart from the others
+				skipped = append(skipped, parser.CardError{Card: i, Field: setters.FieldID, Required: true, Err: setters.ErrMissing})
+				continue
+			}
+			if _, ok := seen[job.ExternalID]; ok {
+				continue
+			}
+			seen[job.ExternalID] = struct{}{}
+			jobs = append(jobs, job)
+		}
+	}
+
+	if len(skipped) > 0 {
+		s.logger.Warn("Some xhr jobs were skipped",
+			zap.Int("rejected", len(skipped)),
+			zap.Int("kept", len(jobs)),
+			zap.Errors("errors", skipped))
+	}
+
+	if len(jobs) == 0 {
+		return nil, loader.ErrEmptyResults
+	}
+
+	s.logger.Debug("Captured xhr jobs", zap.Int("responses", len(bodies)), zap.Int("jobs", len(jobs)))
+	return jobs, nil
+}
+
+func (s *XhrSource) Close() error {
+	return s.loader.Close()
+}
+
+func (job xhrJob) toDomain(pageUrl string) domain.Job {
+	result := domain.Job{
+		Source:      sites.Jooble,
+		ExternalID:  job.Uid.String(),
+		Title:       stripTags(job.Position),
+		Company:     strings.TrimSpace(job.Company.Name),
+		City:        strings.TrimSpace(job.Location.Name),
+		Salary:      strings.TrimSpace(job.Salary),
+		Link:        resolveLink(pageUrl, job.Url),
+		Description: stripTags(job.Content),
+		WorkType:    strings.TrimSpace(job.JobType),
+		Date:        strings.TrimSpace(job.DateCaption),
+	}
+
+	for _, tag := range job.Tags {
+		if name := strings.TrimSpace(tag.Name); name != "" {
+			result.Tags = append(result.Tags, name)
+		}
+	}
+
+	return result
+}
+
+func (n *xhrNamed) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		n.Name = name
+		return nil
+	}
+
+	type named xhrNamed
+	return json.Unmarshal(data, (*named)(n))
+}
+
+func resolveLink(pageUrl string, link string) string {
+	base, err := url.Parse(pageUrl)
+	if err != nil {
+		return link
+	}
+	ref, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return link
+	}
+	return base.ResolveReference(ref).String()
+}